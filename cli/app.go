@@ -15,12 +15,15 @@ import (
 	hashicorpversion "github.com/hashicorp/go-version"
 
 	awsproviderpatch "github.com/gruntwork-io/terragrunt/cli/commands/aws-provider-patch"
+	"github.com/gruntwork-io/terragrunt/cli/commands/drift"
+	"github.com/gruntwork-io/terragrunt/cli/commands/graph"
 	graphdependencies "github.com/gruntwork-io/terragrunt/cli/commands/graph-dependencies"
 	"github.com/gruntwork-io/terragrunt/cli/commands/hclfmt"
 	renderjson "github.com/gruntwork-io/terragrunt/cli/commands/render-json"
 	runall "github.com/gruntwork-io/terragrunt/cli/commands/run-all"
 	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
 	terragruntinfo "github.com/gruntwork-io/terragrunt/cli/commands/terragrunt-info"
+	validateconfig "github.com/gruntwork-io/terragrunt/cli/commands/validate-config"
 	validateinputs "github.com/gruntwork-io/terragrunt/cli/commands/validate-inputs"
 	"github.com/gruntwork-io/terragrunt/cli/flags"
 	"github.com/gruntwork-io/terragrunt/options"
@@ -64,10 +67,13 @@ func newCommands(opts *options.TerragruntOptions) cli.Commands {
 		runall.NewCommand(opts),            // run-all
 		terragruntinfo.NewCommand(opts),    // terragrunt-info
 		validateinputs.NewCommand(opts),    // validate-inputs
+		validateconfig.NewCommand(opts),    // validate-config
 		graphdependencies.NewCommand(opts), // graph-dependencies
+		graph.NewCommand(opts),             // graph
 		hclfmt.NewCommand(opts),            // hclfmt
 		renderjson.NewCommand(opts),        // render-json
 		awsproviderpatch.NewCommand(opts),  // aws-provider-patch
+		drift.NewCommand(opts),             // drift
 	}
 
 	sort.Sort(cmds)
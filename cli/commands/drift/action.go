@@ -0,0 +1,37 @@
+// `drift` command runs `terraform plan -refresh-only -detailed-exitcode` in the module and reports whether the real
+// infrastructure has drifted from the state, and which resources. It exits with code 2 if the module has drifted, so
+// that scheduled drift checks can tell drift apart from errors. `run-all drift` does the same for every module of the
+// stack, see configstack.
+
+package drift
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+)
+
+func Run(opts *options.TerragruntOptions) error {
+	drift, err := configstack.DetectDrift(opts)
+	if err != nil {
+		return err
+	}
+
+	if !drift.Drifted {
+		opts.Logger.Infof("No drift detected in %s", opts.WorkingDir)
+		return nil
+	}
+
+	if _, err := fmt.Fprintln(opts.Writer, "Drifted resources:"); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	for _, resource := range drift.Resources {
+		if _, err := fmt.Fprintf(opts.Writer, "  %s\n", resource); err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+
+	return errors.WithStackTrace(configstack.DriftDetected{Paths: []string{opts.WorkingDir}})
+}
@@ -0,0 +1,27 @@
+package drift
+
+import (
+	"github.com/gruntwork-io/terragrunt/cli/flags"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "drift"
+)
+
+var (
+	TerragruntFlagNames = append(flags.CommonFlagNames,
+		flags.FlagNameTerragruntConfig,
+	)
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:   CommandName,
+		Usage:  "Checks whether the real infrastructure has drifted from the state, with plan -refresh-only -detailed-exitcode.",
+		Flags:  flags.NewFlags(opts).Filter(TerragruntFlagNames),
+		Before: func(ctx *cli.Context) error { return ctx.App.Before(ctx) },
+		Action: func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx)) },
+	}
+}
@@ -12,7 +12,5 @@ func Run(opts *options.TerragruntOptions) error {
 		return err
 	}
 
-	// Exit early if the operation wanted is to get the graph
-	stack.Graph(opts)
-	return nil
+	return stack.Graph(opts)
 }
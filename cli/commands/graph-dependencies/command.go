@@ -13,6 +13,8 @@ const (
 var (
 	TerragruntFlagNames = append(flags.CommonFlagNames,
 		flags.FlagNameTerragruntConfig,
+		flags.FlagNameFormat,
+		flags.FlagNameRunReport,
 	)
 )
 
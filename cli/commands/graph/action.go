@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The command whose run order the --order flag prints. Destroy runs the groups in the reverse order.
+const runOrderCommand = "apply"
+
+// Run prints the modules of the stack grouped in run order if --order is set. Otherwise, `terraform graph` is run, as
+// before the graph command existed.
+func Run(opts *options.TerragruntOptions) error {
+	if !opts.GraphRunOrder {
+		return terraform.Run(opts)
+	}
+
+	stack, err := configstack.FindStackInSubfolders(opts, nil)
+	if err != nil {
+		return err
+	}
+
+	return printModules(opts, stack, stack.Modules)
+}
+
+// RunDependents prints the modules of the stack that depend on the module in the given folder.
+func RunDependents(opts *options.TerragruntOptions, modulePath string) error {
+	return runQuery(opts, modulePath, func(stack *configstack.Stack, module *configstack.TerraformModule) []*configstack.TerraformModule {
+		return stack.FindDependents(module, opts.GraphTransitive)
+	})
+}
+
+// RunDependencies prints the modules of the stack that the module in the given folder depends on.
+func RunDependencies(opts *options.TerragruntOptions, modulePath string) error {
+	return runQuery(opts, modulePath, func(stack *configstack.Stack, module *configstack.TerraformModule) []*configstack.TerraformModule {
+		return stack.FindDependencies(module, opts.GraphTransitive)
+	})
+}
+
+func runQuery(opts *options.TerragruntOptions, modulePath string, query func(*configstack.Stack, *configstack.TerraformModule) []*configstack.TerraformModule) error {
+	if modulePath == "" {
+		return errors.WithStackTrace(MissingModulePath{})
+	}
+
+	canonicalModulePath, err := util.CanonicalPath(modulePath, opts.WorkingDir)
+	if err != nil {
+		return err
+	}
+
+	stack, err := configstack.FindStackInSubfolders(opts, nil)
+	if err != nil {
+		return err
+	}
+
+	module, err := stack.FindModuleByPath(canonicalModulePath)
+	if err != nil {
+		return err
+	}
+
+	return printModules(opts, stack, query(stack, module))
+}
+
+// Prints the paths of the given modules, relative to the working dir, as plain text or JSON. With --order, the modules
+// are grouped in run order.
+func printModules(opts *options.TerragruntOptions, stack *configstack.Stack, modules []*configstack.TerraformModule) error {
+	if !opts.GraphRunOrder {
+		paths, err := relativeModulePaths(opts, modules)
+		if err != nil {
+			return err
+		}
+		if opts.GraphJSON {
+			return writeJSON(opts.Writer, paths)
+		}
+		for _, path := range paths {
+			if _, err := fmt.Fprintln(opts.Writer, path); err != nil {
+				return errors.WithStackTrace(err)
+			}
+		}
+		return nil
+	}
+
+	groups, err := stack.GetRunGroups(runOrderCommand, modules)
+	if err != nil {
+		return err
+	}
+
+	groupPaths := [][]string{}
+	for _, group := range groups {
+		paths, err := relativeModulePaths(opts, group)
+		if err != nil {
+			return err
+		}
+		groupPaths = append(groupPaths, paths)
+	}
+
+	if opts.GraphJSON {
+		return writeJSON(opts.Writer, groupPaths)
+	}
+	for i, paths := range groupPaths {
+		if i > 0 {
+			if _, err := fmt.Fprintln(opts.Writer); err != nil {
+				return errors.WithStackTrace(err)
+			}
+		}
+		if _, err := fmt.Fprintf(opts.Writer, "Group %d\n", i+1); err != nil {
+			return errors.WithStackTrace(err)
+		}
+		for _, path := range paths {
+			if _, err := fmt.Fprintln(opts.Writer, path); err != nil {
+				return errors.WithStackTrace(err)
+			}
+		}
+	}
+	return nil
+}
+
+func relativeModulePaths(opts *options.TerragruntOptions, modules []*configstack.TerraformModule) ([]string, error) {
+	paths := []string{}
+	for _, module := range modules {
+		path, err := util.GetPathRelativeTo(module.Path, opts.WorkingDir)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func writeJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.WithStackTrace(encoder.Encode(value))
+}
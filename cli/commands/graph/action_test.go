@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testFixtureGraphDependencies = "../../../test/fixture-graph-dependencies/root"
+
+func newGraphTestOptions(t *testing.T) (*options.TerragruntOptions, *bytes.Buffer) {
+	workingDir, err := filepath.Abs(testFixtureGraphDependencies)
+	require.NoError(t, err)
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(workingDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.WorkingDir = filepath.ToSlash(workingDir)
+
+	var out bytes.Buffer
+	opts.Writer = &out
+	return opts, &out
+}
+
+func TestRunDependents(t *testing.T) {
+	t.Parallel()
+
+	opts, out := newGraphTestOptions(t)
+	require.NoError(t, RunDependents(opts, "./vpc"))
+	assert.Equal(t, []string{"backend-app", "frontend-app", "mysql", "redis"}, strings.Fields(out.String()))
+
+	opts, out = newGraphTestOptions(t)
+	opts.GraphTransitive = true
+	require.NoError(t, RunDependents(opts, "mysql"))
+	assert.Equal(t, []string{"backend-app", "frontend-app"}, strings.Fields(out.String()))
+}
+
+func TestRunDependenciesTransitiveJSON(t *testing.T) {
+	t.Parallel()
+
+	opts, out := newGraphTestOptions(t)
+	opts.GraphTransitive = true
+	opts.GraphJSON = true
+	require.NoError(t, RunDependencies(opts, "backend-app"))
+
+	var paths []string
+	require.NoError(t, json.Unmarshal(out.Bytes(), &paths))
+	assert.Equal(t, []string{"mysql", "redis", "vpc"}, paths)
+}
+
+func TestRunOrderJSON(t *testing.T) {
+	t.Parallel()
+
+	opts, out := newGraphTestOptions(t)
+	opts.GraphRunOrder = true
+	opts.GraphJSON = true
+	require.NoError(t, Run(opts))
+
+	var groups [][]string
+	require.NoError(t, json.Unmarshal(out.Bytes(), &groups))
+	assert.Equal(t, [][]string{{"vpc"}, {"mysql", "redis"}, {"backend-app"}, {"frontend-app"}}, groups)
+}
+
+func TestRunQueryErrors(t *testing.T) {
+	t.Parallel()
+
+	opts, _ := newGraphTestOptions(t)
+	assert.Error(t, RunDependents(opts, ""))
+	assert.Error(t, RunDependencies(opts, "./does-not-exist"))
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(_ []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestRunDependentsWriteError(t *testing.T) {
+	t.Parallel()
+
+	opts, _ := newGraphTestOptions(t)
+	opts.Writer = failingWriter{}
+	assert.ErrorContains(t, RunDependents(opts, "./vpc"), "write failed")
+
+	opts, _ = newGraphTestOptions(t)
+	opts.Writer = failingWriter{}
+	opts.GraphRunOrder = true
+	assert.ErrorContains(t, Run(opts), "write failed")
+}
@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"github.com/gruntwork-io/terragrunt/cli/flags"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "graph"
+
+	SubcommandNameDependents   = "dependents"
+	SubcommandNameDependencies = "dependencies"
+)
+
+var (
+	TerragruntFlagNames = append(flags.CommonFlagNames,
+		flags.FlagNameTerragruntConfig,
+		flags.FlagNameOrder,
+		flags.FlagNameJSON,
+	)
+
+	QueryFlagNames = append(TerragruntFlagNames,
+		flags.FlagNameTransitive,
+	)
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Query the dependency graph of the stack. Without a subcommand or --order, runs terraform graph.",
+		Description: "With --order, prints the modules of the stack grouped in the order in which run-all would run them. The dependents and dependencies subcommands list the modules connected to a module.",
+		Subcommands: newSubcommands(opts),
+		Flags:       flags.NewFlags(opts).Filter(TerragruntFlagNames),
+		Before:      func(ctx *cli.Context) error { return ctx.App.Before(ctx) },
+		Action:      func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx)) },
+	}
+}
+
+func newSubcommands(opts *options.TerragruntOptions) cli.Commands {
+	return cli.Commands{
+		&cli.Command{
+			Name:      SubcommandNameDependents,
+			Usage:     "List the modules of the stack that depend on the given module.",
+			UsageText: "terragrunt graph dependents <module-path> [--transitive] [--order] [--json]",
+			Flags:     flags.NewFlags(opts).Filter(QueryFlagNames),
+			Before:    func(ctx *cli.Context) error { return ctx.App.Before(ctx) },
+			Action: func(ctx *cli.Context) error {
+				return RunDependents(opts.OptionsFromContext(ctx), ctx.Args().First())
+			},
+		},
+		&cli.Command{
+			Name:      SubcommandNameDependencies,
+			Usage:     "List the modules of the stack that the given module depends on.",
+			UsageText: "terragrunt graph dependencies <module-path> [--transitive] [--order] [--json]",
+			Flags:     flags.NewFlags(opts).Filter(QueryFlagNames),
+			Before:    func(ctx *cli.Context) error { return ctx.App.Before(ctx) },
+			Action: func(ctx *cli.Context) error {
+				return RunDependencies(opts.OptionsFromContext(ctx), ctx.Args().First())
+			},
+		},
+	}
+}
@@ -0,0 +1,7 @@
+package graph
+
+type MissingModulePath struct{}
+
+func (err MissingModulePath) Error() string {
+	return "Missing module path argument (Example: terragrunt graph dependents ./vpc)"
+}
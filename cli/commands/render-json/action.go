@@ -85,6 +85,8 @@ func marshalCtyValueJSONWithoutType(ctyVal cty.Value) ([]byte, error) {
 		return nil, errors.WithStackTrace(err)
 	}
 
-	jsonBytes, err := json.Marshal(ctyJsonOutput.Value)
+	// The values of secrets, such as the ones marked with sensitive(), are redacted, as the rendered config often ends up
+	// in CI logs and artifacts
+	jsonBytes, err := json.Marshal(util.RedactSecretsInValue(ctyJsonOutput.Value))
 	return jsonBytes, errors.WithStackTrace(err)
 }
@@ -1,6 +1,7 @@
 package runall
 
 import (
+	validateconfig "github.com/gruntwork-io/terragrunt/cli/commands/validate-config"
 	"github.com/gruntwork-io/terragrunt/configstack"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/pkg/errors"
@@ -38,11 +39,22 @@ func Run(opts *options.TerragruntOptions) error {
 		}
 	}
 
+	// Configs are validated without building the stack, as building it fails at the first config that doesn't parse
+	if opts.TerraformCommand == validateconfig.CommandName {
+		return validateconfig.RunAll(opts)
+	}
+
 	stack, err := configstack.FindStackInSubfolders(opts, nil)
 	if err != nil {
 		return err
 	}
 
+	if opts.Resume {
+		if _, err := stack.ResumeFromCheckpoint(opts); err != nil {
+			return err
+		}
+	}
+
 	opts.Logger.Debugf("%s", stack.String())
 	if err := stack.LogModuleDeployOrder(opts.Logger, opts.TerraformCommand); err != nil {
 		return err
@@ -57,6 +69,10 @@ func Run(opts *options.TerragruntOptions) error {
 	case "state":
 		prompt = "Are you sure you want to manipulate the state with `terragrunt state` in each folder of the stack described above? Note that absolute paths are shared, while relative paths will be relative to each working directory."
 	}
+	// With --terragrunt-run-all-confirm-each, the plan of each module is confirmed instead
+	if opts.RunAllConfirmEach && (opts.TerraformCommand == "apply" || opts.TerraformCommand == "destroy") {
+		prompt = ""
+	}
 	if prompt != "" {
 		shouldRunAll, err := shell.PromptUserForYesNo(prompt, opts)
 		if err != nil {
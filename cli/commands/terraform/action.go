@@ -1,9 +1,12 @@
 package terraform
 
 import (
+	"context"
+	goerrors "errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
@@ -86,7 +89,7 @@ func RunWithTarget(opts *options.TerragruntOptions, target *Target) error {
 	return runTerraform(opts, target)
 }
 
-func runTerraform(terragruntOptions *options.TerragruntOptions, target *Target) error {
+func runTerraform(terragruntOptions *options.TerragruntOptions, target *Target) (finalErr error) {
 	if err := checkVersionConstraints(terragruntOptions); err != nil {
 		return err
 	}
@@ -157,6 +160,32 @@ func runTerraform(terragruntOptions *options.TerragruntOptions, target *Target)
 		terragruntOptions.RetrySleepIntervalSec = time.Duration(*terragruntConfig.RetrySleepIntervalSec) * time.Second
 	}
 
+	timeout, err := getTerraformTimeout(terragruntOptions, terragruntConfig)
+	if err != nil {
+		return err
+	}
+
+	// The timeout covers everything from here on. The commands that are still running when it expires, such as
+	// terraform or hooks, are interrupted and killed if they don't shut down within the grace period.
+	if timeout > 0 {
+		originalCtx := terragruntOptions.Context
+		parentCtx := originalCtx
+		if parentCtx == nil {
+			parentCtx = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(parentCtx, timeout)
+		defer cancel()
+		ctx = context.WithValue(ctx, withoutTimeoutContextKey{}, parentCtx)
+		terragruntOptions.Context = ctx
+
+		defer func() {
+			terragruntOptions.Context = originalCtx
+			if finalErr != nil && goerrors.Is(ctx.Err(), context.DeadlineExceeded) {
+				finalErr = errors.WithStackTrace(configstack.ModuleTimedOut{Path: terragruntOptions.WorkingDir, Timeout: timeout, Err: finalErr})
+			}
+		}()
+	}
+
 	updatedTerragruntOptions := terragruntOptions
 	sourceUrl, err := config.GetTerraformSourceUrl(terragruntOptions, terragruntConfig)
 	if err != nil {
@@ -207,6 +236,28 @@ func runTerraform(terragruntOptions *options.TerragruntOptions, target *Target)
 	return runTerragruntWithConfig(terragruntOptions, updatedTerragruntOptions, terragruntConfig, false, target)
 }
 
+// The key of the value of the context of a module with a timeout that holds the context of the module without the
+// timeout, in which the after and error hooks run.
+type withoutTimeoutContextKey struct{}
+
+// Returns the maximum time the module may run for. The --terragrunt-timeout flag takes precedence over the
+// terraform_timeout attribute of the config. Returns 0 if neither is set.
+func getTerraformTimeout(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) (time.Duration, error) {
+	timeout := terragruntOptions.Timeout
+	if timeout == "" && terragruntConfig.TerraformTimeout != nil {
+		timeout = *terragruntConfig.TerraformTimeout
+	}
+	if timeout == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(timeout)
+	if err != nil || duration <= 0 {
+		return 0, errors.WithStackTrace(InvalidTimeout(timeout))
+	}
+	return duration, nil
+}
+
 func generateConfig(terragruntConfig *config.TerragruntConfig, updatedTerragruntOptions *options.TerragruntOptions) error {
 	rawActualLock, _ := sourceChangeLocks.LoadOrStore(updatedTerragruntOptions.DownloadDir, &sync.Mutex{})
 	actualLock := rawActualLock.(*sync.Mutex)
@@ -270,9 +321,20 @@ func runTerragruntWithConfig(originalTerragruntOptions *options.TerragruntOption
 		return err
 	}
 
+	planFile := ""
+	if terragruntOptions.PlanJSONFile != "" && util.FirstArg(terragruntOptions.TerraformCliArgs) == CommandNamePlan {
+		planFile = preparePlanFile(terragruntOptions)
+	}
+
 	return runActionWithHooks("terraform", terragruntOptions, terragruntConfig, func() error {
 		runTerraformError := runTerraformWithRetry(terragruntOptions)
 
+		if planFile != "" && (runTerraformError == nil || shell.IsPlanWithChanges(terragruntOptions.TerraformCliArgs, runTerraformError)) {
+			if err := writePlanJSON(terragruntOptions, planFile); err != nil {
+				terragruntOptions.Logger.Warnf("Failed to save the plan of %s as JSON: %v", terragruntOptions.WorkingDir, err)
+			}
+		}
+
 		var lockFileError error
 		if shouldCopyLockFile(terragruntOptions.TerraformCliArgs) {
 			// Copy the lock file from the Terragrunt working dir (e.g., .terragrunt-cache/xxx/<some-module>) to the
@@ -289,6 +351,33 @@ func runTerragruntWithConfig(originalTerragruntOptions *options.TerragruntOption
 	})
 }
 
+// Returns the file the plan command saves the plan to. If the command doesn't save the plan already, an -out argument is
+// added that saves it next to the PlanJSONFile.
+func preparePlanFile(terragruntOptions *options.TerragruntOptions) string {
+	args := terragruntOptions.TerraformCliArgs
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-out=") {
+			return strings.TrimPrefix(arg, "-out=")
+		}
+		if arg == "-out" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+
+	planFile := strings.TrimSuffix(terragruntOptions.PlanJSONFile, filepath.Ext(terragruntOptions.PlanJSONFile)) + ".tfplan"
+	terragruntOptions.InsertTerraformCliArgs("-out=" + planFile)
+	return planFile
+}
+
+// Writes the JSON representation of the given plan file, as printed by `terraform show -json`, to the PlanJSONFile.
+func writePlanJSON(terragruntOptions *options.TerragruntOptions, planFile string) error {
+	out, err := shell.RunShellCommandWithOutput(terragruntOptions, "", true, false, terragruntOptions.TerraformPath, "show", "-json", planFile)
+	if err != nil {
+		return err
+	}
+	return errors.WithStackTrace(os.WriteFile(terragruntOptions.PlanJSONFile, []byte(out.Stdout), 0644))
+}
+
 // confirmActionWithDependentModules - Show warning with list of dependent modules from current module before destroy
 func confirmActionWithDependentModules(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) bool {
 	modules := configstack.FindWhereWorkingDirIsIncluded(terragruntOptions, terragruntConfig)
@@ -354,6 +443,15 @@ func runActionWithHooks(description string, terragruntOptions *options.Terragrun
 	} else {
 		terragruntOptions.Logger.Errorf("Errors encountered running before_hooks. Not running '%s'.", description)
 	}
+
+	// The after and error hooks also run when the timeout of the module expired, such as to clean up after the
+	// interrupted action, so they run without the timeout
+	if ctx := terragruntOptions.Context; ctx != nil {
+		if parentCtx, hasTimeout := ctx.Value(withoutTimeoutContextKey{}).(context.Context); hasTimeout {
+			terragruntOptions.Context = parentCtx
+			defer func() { terragruntOptions.Context = ctx }()
+		}
+	}
 	postHookErrors := processHooks(terragruntConfig.Terraform.GetAfterHooks(), terragruntOptions, terragruntConfig, allErrors)
 	errorHookErrors := processErrorHooks(terragruntConfig.Terraform.GetErrorHooks(), terragruntOptions, allErrors)
 	allErrors = multierror.Append(allErrors, postHookErrors, errorHookErrors)
@@ -383,12 +481,23 @@ func setTerragruntInputsAsEnvVars(terragruntOptions *options.TerragruntOptions,
 }
 
 func runTerraformWithRetry(terragruntOptions *options.TerragruntOptions) error {
+	// The sleep between retries stops when the run is cancelled or times out
+	var done <-chan struct{}
+	if terragruntOptions.Context != nil {
+		done = terragruntOptions.Context.Done()
+	}
+
 	// Retry the command configurable time with sleep in between
 	for i := 0; i < terragruntOptions.RetryMaxAttempts; i++ {
 		if out, tferr := shell.RunTerraformCommandWithOutput(terragruntOptions, terragruntOptions.TerraformCliArgs...); tferr != nil {
 			if out != nil && isRetryable(out.Stdout, out.Stderr, tferr, terragruntOptions) {
 				terragruntOptions.Logger.Infof("Encountered an error eligible for retrying. Sleeping %v before retrying.\n", terragruntOptions.RetrySleepIntervalSec)
-				time.Sleep(terragruntOptions.RetrySleepIntervalSec)
+				select {
+				case <-done:
+					terragruntOptions.Logger.Errorf("Terraform invocation failed in %s and the run was interrupted before retrying", terragruntOptions.WorkingDir)
+					return tferr
+				case <-time.After(terragruntOptions.RetrySleepIntervalSec):
+				}
 			} else {
 				terragruntOptions.Logger.Errorf("Terraform invocation failed in %s", terragruntOptions.WorkingDir)
 				return tferr
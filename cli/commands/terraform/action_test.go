@@ -1,11 +1,13 @@
 package terraform
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	goerrors "github.com/go-errors/errors"
 	"github.com/gruntwork-io/terragrunt/config"
@@ -256,6 +258,62 @@ func TestTerragruntHandlesCatastrophicTerraformFailure(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestRunTerraformWithRetryStopsSleepingWhenCancelled(t *testing.T) {
+	t.Parallel()
+
+	tgOptions, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+
+	// A command that always fails with a retryable error, and a sleep between retries that would outlast the test
+	tgOptions.TerraformPath = "sh"
+	tgOptions.TerraformCliArgs = []string{"-c", "echo 'Error: try again' >&2; exit 1"}
+	tgOptions.RetryableErrors = []string{".*try again.*"}
+	tgOptions.RetryMaxAttempts = 3
+	tgOptions.RetrySleepIntervalSec = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	tgOptions.Context = ctx
+
+	startTime := time.Now()
+	err = runTerraformWithRetry(tgOptions)
+	require.Error(t, err)
+	assert.Less(t, time.Since(startTime), time.Minute)
+}
+
+func TestRunActionWithHooksRunsAfterHooksWithoutTimeout(t *testing.T) {
+	t.Parallel()
+
+	tgOptions, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+	tgOptions.TerraformCommand = "apply"
+	tgOptions.WorkingDir = t.TempDir()
+
+	// The timeout of the module has already expired when the after hook runs
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	ctx = context.WithValue(ctx, withoutTimeoutContextKey{}, context.Background())
+	tgOptions.Context = ctx
+
+	runOnError := true
+	terragruntConfig := &config.TerragruntConfig{Terraform: &config.TerraformConfig{
+		AfterHooks: []config.Hook{{
+			Name:       "cleanup",
+			Commands:   []string{"apply"},
+			Execute:    []string{"sh", "-c", "sleep 0.5 && touch cleaned-up"},
+			RunOnError: &runOnError,
+		}},
+	}}
+
+	err = runActionWithHooks("terraform", tgOptions, terragruntConfig, func() error {
+		return ctx.Err()
+	})
+	require.Error(t, err)
+	assert.True(t, util.FileExists(filepath.Join(tgOptions.WorkingDir, "cleaned-up")))
+	assert.Equal(t, ctx, tgOptions.Context)
+}
+
 func TestToTerraformEnvVars(t *testing.T) {
 	t.Parallel()
 
@@ -478,3 +536,74 @@ func createTempFile(t *testing.T) string {
 
 	return filepath.ToSlash(tmpFile.Name())
 }
+
+func TestGetTerraformTimeout(t *testing.T) {
+	t.Parallel()
+
+	configTimeout := "45m"
+	invalidConfigTimeout := "forever"
+
+	testCases := []struct {
+		description   string
+		flagTimeout   string
+		configTimeout *string
+		expected      time.Duration
+		expectedErr   bool
+	}{
+		{"No timeout", "", nil, 0, false},
+		{"Timeout in config", "", &configTimeout, 45 * time.Minute, false},
+		{"Timeout flag", "90s", nil, 90 * time.Second, false},
+		{"Timeout flag overrides config", "1h", &configTimeout, time.Hour, false},
+		{"Invalid timeout in config", "", &invalidConfigTimeout, 0, true},
+		{"Negative timeout flag", "-5m", nil, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.description, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := options.NewTerragruntOptionsForTest("mock-path-for-test.hcl")
+			require.NoError(t, err)
+			opts.Timeout = testCase.flagTimeout
+
+			timeout, err := getTerraformTimeout(opts, &config.TerragruntConfig{TerraformTimeout: testCase.configTimeout})
+			if testCase.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, timeout)
+		})
+	}
+}
+
+func TestPreparePlanFile(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		description      string
+		args             []string
+		expectedPlanFile string
+		expectedArgs     []string
+	}{
+		{"No out arg", []string{"plan", "-input=false"}, "/tmp/summary/module-0.tfplan", []string{"plan", "-out=/tmp/summary/module-0.tfplan", "-input=false"}},
+		{"Out arg with equals sign", []string{"plan", "-out=my.tfplan"}, "my.tfplan", []string{"plan", "-out=my.tfplan"}},
+		{"Out arg with separate value", []string{"plan", "-out", "my.tfplan"}, "my.tfplan", []string{"plan", "-out", "my.tfplan"}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.description, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := options.NewTerragruntOptionsForTest("mock-path-for-test.hcl")
+			require.NoError(t, err)
+			opts.TerraformCliArgs = testCase.args
+			opts.PlanJSONFile = "/tmp/summary/module-0.json"
+
+			assert.Equal(t, testCase.expectedPlanFile, preparePlanFile(opts))
+			assert.Equal(t, testCase.expectedArgs, opts.TerraformCliArgs)
+		})
+	}
+}
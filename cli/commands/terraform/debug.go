@@ -80,7 +80,8 @@ func terragruntDebugFileContents(
 		// We must do this in order to avoid overriding the env var when the user follows up with a direct invocation to
 		// terraform using this file (due to the order in which terraform resolves config sources).
 		if !varIsInEnv && varIsDefined {
-			jsonValuesByKey[varName] = varValue
+			// The values of secrets are redacted, so that the debug file can be shared or kept without leaking them
+			jsonValuesByKey[varName] = util.RedactSecretsInValue(varValue)
 		} else if varIsInEnv {
 			terragruntOptions.Logger.Debugf(
 				"WARN: The variable %s was omitted from the debug file because the env var %s is already set.",
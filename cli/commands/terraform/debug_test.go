@@ -0,0 +1,28 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerragruntDebugFileContentsRedactsSecrets(t *testing.T) {
+	t.Parallel()
+
+	util.RegisterSecretValue("debug-test-password")
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("mock-path-for-test.hcl")
+	require.NoError(t, err)
+	terragruntConfig := &config.TerragruntConfig{Inputs: map[string]interface{}{
+		"region": "us-east-1",
+		"db":     map[string]interface{}{"password": "debug-test-password"},
+	}}
+
+	contents, err := terragruntDebugFileContents(terragruntOptions, terragruntConfig, []string{"region", "db"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"region":"us-east-1","db":{"password":"[REDACTED]"}}`, string(contents))
+}
@@ -45,3 +45,9 @@ type MaxRetriesExceeded struct {
 func (err MaxRetriesExceeded) Error() string {
 	return fmt.Sprintf("Exhausted retries (%v) for command %v %v", err.Opts.RetryMaxAttempts, err.Opts.TerraformPath, strings.Join(err.Opts.TerraformCliArgs, " "))
 }
+
+type InvalidTimeout string
+
+func (timeout InvalidTimeout) Error() string {
+	return fmt.Sprintf("Invalid timeout %q. It must be a positive duration such as 90s, 30m or 1h.", string(timeout))
+}
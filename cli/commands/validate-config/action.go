@@ -0,0 +1,142 @@
+// `validate-config` command parses the terragrunt config of the module, and the configs it includes, the same way as
+// running the module would, but without running terraform or fetching the outputs of dependencies, which are replaced
+// with their mock_outputs. Every problem found is printed with its file, line and column, either one line per problem
+// like a compiler, or as a SARIF log that code review tools can show as annotations. `run-all validate-config` does the
+// same for every config under the working dir. It does not build the stack, as that stops at the first broken config.
+
+package validateconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	// FormatText prints one line per problem, like a compiler: file:line:column: severity: summary: detail
+	FormatText = "text"
+
+	// FormatSARIF prints a SARIF 2.1.0 log with one result per problem
+	FormatSARIF = "sarif"
+)
+
+func Run(opts *options.TerragruntOptions) error {
+	return validateConfigs(opts, []string{opts.TerragruntConfigPath})
+}
+
+// RunAll validates every terragrunt config under the working dir.
+func RunAll(opts *options.TerragruntOptions) error {
+	configPaths, err := config.FindConfigFilesInPath(opts.WorkingDir, opts)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return validateConfigs(opts, configPaths)
+}
+
+// Validates the configs at the given paths, prints the problems found in the format set with
+// --terragrunt-diagnostics-format, and returns an InvalidConfigs error if any of them is an error. The problems of a
+// config included by several of the given configs are only printed once.
+func validateConfigs(opts *options.TerragruntOptions, configPaths []string) error {
+	if opts.DiagnosticsFormat != "" && opts.DiagnosticsFormat != FormatText && opts.DiagnosticsFormat != FormatSARIF {
+		return errors.WithStackTrace(UnsupportedDiagnosticsFormat(opts.DiagnosticsFormat))
+	}
+
+	var diags hcl.Diagnostics
+	seen := map[string]bool{}
+	invalidConfigs := 0
+	for _, configPath := range configPaths {
+		opts.Logger.Debugf("Validating %s", configPath)
+
+		configOpts := opts.Clone(configPath)
+		configDiags := config.ValidateConfigFile(configPath, configOpts)
+		if configDiags.HasErrors() {
+			invalidConfigs++
+		}
+
+		for _, diag := range configDiags {
+			if diag.Subject == nil {
+				diag.Subject = &hcl.Range{Filename: configPath}
+			}
+			key := fmt.Sprintf("%s:%d:%d:%s:%s", diag.Subject.Filename, diag.Subject.Start.Line, diag.Subject.Start.Column, diag.Summary, diag.Detail)
+			if !seen[key] {
+				seen[key] = true
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].Subject.Filename != diags[j].Subject.Filename {
+			return diags[i].Subject.Filename < diags[j].Subject.Filename
+		}
+		return diags[i].Subject.Start.Byte < diags[j].Subject.Start.Byte
+	})
+
+	var err error
+	if opts.DiagnosticsFormat == FormatSARIF {
+		err = writeSARIF(opts, diags)
+	} else {
+		err = writeText(opts, diags)
+	}
+	if err != nil {
+		return err
+	}
+
+	errorCount := 0
+	for _, diag := range diags {
+		if diag.Severity == hcl.DiagError {
+			errorCount++
+		}
+	}
+	if errorCount > 0 {
+		return errors.WithStackTrace(InvalidConfigs{Errors: errorCount, Configs: invalidConfigs})
+	}
+
+	opts.Logger.Infof("No errors found in %d Terragrunt config(s)", len(configPaths))
+	return nil
+}
+
+// Prints the given diagnostics one per line, with the position of each, if known, as file:line:column.
+func writeText(opts *options.TerragruntOptions, diags hcl.Diagnostics) error {
+	for _, diag := range diags {
+		location := relativePath(opts, diag.Subject.Filename)
+		if diag.Subject.Start.Line > 0 {
+			location = fmt.Sprintf("%s:%d:%d", location, diag.Subject.Start.Line, diag.Subject.Start.Column)
+		}
+
+		message := diag.Summary
+		if diag.Detail != "" {
+			message = fmt.Sprintf("%s: %s", message, diag.Detail)
+		}
+
+		if _, err := fmt.Fprintf(opts.Writer, "%s: %s: %s\n", location, severityName(diag.Severity), util.RedactSecrets(message)); err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+	return nil
+}
+
+// Returns the given path relative to the working dir, with forward slashes, or the path as is if it can't be made
+// relative.
+func relativePath(opts *options.TerragruntOptions, path string) string {
+	relPath, err := util.GetPathRelativeTo(path, opts.WorkingDir)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(relPath)
+}
+
+func severityName(severity hcl.DiagnosticSeverity) string {
+	if severity == hcl.DiagWarning {
+		return "warning"
+	}
+	return "error"
+}
@@ -0,0 +1,100 @@
+package validateconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	goerrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// Creates a stack with a valid module, a module with an unknown attribute and a module with a syntax error, and returns
+// options for running in its root.
+func createTestStack(t *testing.T) *options.TerragruntOptions {
+	tmpDir := t.TempDir()
+	configs := map[string]string{
+		"vpc": `inputs = { cidr = "10.0.0.0/16" }`,
+		"app": "dependency \"vpc\" {\n  config_path = \"../vpc\"\n}\n\ninput = {\n  vpc_id = dependency.vpc.outputs.id\n}\n",
+		"db":  "inputs = {\n  size =\n}\n",
+	}
+	for name, contents := range configs {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, name), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name, config.DefaultTerragruntConfigPath), []byte(contents), 0644))
+	}
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(tmpDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	return opts
+}
+
+func TestRunAllText(t *testing.T) {
+	t.Parallel()
+
+	opts := createTestStack(t)
+	var out bytes.Buffer
+	opts.Writer = &out
+
+	err := RunAll(opts)
+	var invalidConfigs InvalidConfigs
+	require.True(t, goerrors.As(err, &invalidConfigs))
+	assert.Equal(t, InvalidConfigs{Errors: 2, Configs: 2}, invalidConfigs)
+
+	assert.Equal(t, `app/terragrunt.hcl:5:1: error: Unsupported argument: An argument named "input" is not expected here. Did you mean "inputs"?
+db/terragrunt.hcl:2:9: error: Invalid expression: Expected the start of an expression, but found an invalid expression token.
+`, out.String())
+}
+
+func TestRunAllSARIF(t *testing.T) {
+	t.Parallel()
+
+	opts := createTestStack(t)
+	var out bytes.Buffer
+	opts.Writer = &out
+	opts.DiagnosticsFormat = FormatSARIF
+
+	require.Error(t, RunAll(opts))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(out.Bytes(), &log))
+	assert.Equal(t, sarifVersion, log.Version)
+	require.Len(t, log.Runs, 1)
+	require.Len(t, log.Runs[0].Results, 2)
+
+	result := log.Runs[0].Results[0]
+	assert.Equal(t, "unsupported-argument", result.RuleID)
+	assert.Equal(t, "error", result.Level)
+	assert.Equal(t, "app/terragrunt.hcl", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, &sarifRegion{StartLine: 5, StartColumn: 1, EndLine: 5, EndColumn: 6}, result.Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, []sarifRule{
+		{ID: "unsupported-argument", ShortDescription: sarifMessage{Text: "Unsupported argument"}},
+		{ID: "invalid-expression", ShortDescription: sarifMessage{Text: "Invalid expression"}},
+	}, log.Runs[0].Tool.Driver.Rules)
+}
+
+func TestRunValidConfig(t *testing.T) {
+	t.Parallel()
+
+	opts := createTestStack(t)
+	var out bytes.Buffer
+	opts.Writer = &out
+	opts = opts.Clone(filepath.Join(filepath.Dir(opts.TerragruntConfigPath), "vpc", config.DefaultTerragruntConfigPath))
+
+	require.NoError(t, Run(opts))
+	assert.Empty(t, out.String())
+}
+
+func TestRunUnsupportedDiagnosticsFormat(t *testing.T) {
+	t.Parallel()
+
+	opts := createTestStack(t)
+	opts.DiagnosticsFormat = "junit"
+
+	assert.EqualError(t, Run(opts), UnsupportedDiagnosticsFormat("junit").Error())
+}
@@ -0,0 +1,27 @@
+package validateconfig
+
+import (
+	"github.com/gruntwork-io/terragrunt/cli/flags"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "validate-config"
+)
+
+var (
+	TerragruntFlagNames = append(flags.CommonFlagNames,
+		flags.FlagNameTerragruntConfig,
+	)
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:   CommandName,
+		Usage:  "Parses the terragrunt config, without running terraform, and reports every problem found in it.",
+		Flags:  flags.NewFlags(opts).Filter(TerragruntFlagNames),
+		Before: func(ctx *cli.Context) error { return ctx.App.Before(ctx) },
+		Action: func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx)) },
+	}
+}
@@ -0,0 +1,20 @@
+package validateconfig
+
+import (
+	"fmt"
+)
+
+type InvalidConfigs struct {
+	Errors  int
+	Configs int
+}
+
+func (err InvalidConfigs) Error() string {
+	return fmt.Sprintf("Found %d error(s) in %d Terragrunt config(s)", err.Errors, err.Configs)
+}
+
+type UnsupportedDiagnosticsFormat string
+
+func (format UnsupportedDiagnosticsFormat) Error() string {
+	return fmt.Sprintf("Unsupported diagnostics format %q. Supported formats are: %s, %s", string(format), FormatText, FormatSARIF)
+}
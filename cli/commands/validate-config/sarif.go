@@ -0,0 +1,136 @@
+package validateconfig
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+var nonRuleIDChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// The subset of the SARIF 2.1.0 format used to report diagnostics, see
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// Prints the given diagnostics as a SARIF log with a single run. Each distinct diagnostic summary becomes a rule, so
+// that tools can group the results, and the files are referenced relative to the working dir.
+func writeSARIF(opts *options.TerragruntOptions, diags hcl.Diagnostics) error {
+	driver := sarifDriver{Name: "terragrunt", InformationURI: "https://terragrunt.gruntwork.io", Rules: []sarifRule{}}
+	if opts.TerragruntVersion != nil {
+		driver.Version = opts.TerragruntVersion.String()
+	}
+
+	results := []sarifResult{}
+	rules := map[string]bool{}
+	for _, diag := range diags {
+		ruleID := sarifRuleID(diag.Summary)
+		if !rules[ruleID] {
+			rules[ruleID] = true
+			driver.Rules = append(driver.Rules, sarifRule{ID: ruleID, ShortDescription: sarifMessage{Text: diag.Summary}})
+		}
+
+		message := diag.Summary
+		if diag.Detail != "" {
+			message = diag.Detail
+		}
+
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: relativePath(opts, diag.Subject.Filename)}}
+		if diag.Subject.Start.Line > 0 {
+			location.Region = &sarifRegion{
+				StartLine:   diag.Subject.Start.Line,
+				StartColumn: diag.Subject.Start.Column,
+				EndLine:     diag.Subject.End.Line,
+				EndColumn:   diag.Subject.End.Column,
+			}
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    ruleID,
+			Level:     severityName(diag.Severity),
+			Message:   sarifMessage{Text: util.RedactSecrets(message)},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+	encoder := json.NewEncoder(opts.Writer)
+	encoder.SetIndent("", "  ")
+	return errors.WithStackTrace(encoder.Encode(log))
+}
+
+// Returns the summary of a diagnostic in kebab case, such as unsupported-argument, to be used as the ID of its rule.
+func sarifRuleID(summary string) string {
+	return strings.Trim(nonRuleIDChars.ReplaceAllString(strings.ToLower(summary), "-"), "-")
+}
@@ -45,6 +45,32 @@ const (
 	FlagNameTerragruntJSONOut                        = "terragrunt-json-out"
 	FlagNameWithMetadata                             = "with-metadata"
 	FlagTerragruntStrictValidate                     = "terragrunt-strict-validate"
+	FlagNameTerragruntReportFile                     = "terragrunt-report-file"
+	FlagNameTerragruntReportFormat                   = "terragrunt-report-format"
+	FlagNameTerragruntResume                         = "terragrunt-resume"
+	FlagNameTerragruntCheckpointFile                 = "terragrunt-checkpoint-file"
+	FlagNameTerragruntFailFast                       = "terragrunt-fail-fast"
+	FlagNameTerragruntChangedSince                   = "terragrunt-changed-since"
+	FlagNameTerragruntTimeout                        = "terragrunt-timeout"
+	FlagNameTerragruntConcurrencyLimit               = "terragrunt-concurrency-limit"
+	FlagNameTerragruntPlanSummary                    = "terragrunt-plan-summary"
+	FlagNameTerragruntShard                          = "terragrunt-shard"
+	FlagNameTerragruntShardMarkerStore               = "terragrunt-shard-marker-store"
+	FlagNameTerragruntShardWaitTimeout               = "terragrunt-shard-wait-timeout"
+	FlagNameTerragruntShardRunID                     = "terragrunt-shard-run-id"
+	FlagNameTerragruntDiscoveryCacheFile             = "terragrunt-discovery-cache-file"
+	FlagNameTerragruntRunAllConfirmEach              = "terragrunt-run-all-confirm-each"
+	FlagNameTerragruntMergeJSON                      = "terragrunt-merge-json"
+	FlagNameTerragruntRunAllRetryMaxAttempts         = "terragrunt-run-all-retry-max-attempts"
+	FlagNameTerragruntOutputMode                     = "terragrunt-output-mode"
+	FlagNameTerragruntDiagnosticsFormat              = "terragrunt-diagnostics-format"
+	FlagNameTerragruntNoCmdCache                     = "terragrunt-no-cmd-cache"
+	FlagNameTerragruntCmdCacheDir                    = "terragrunt-cmd-cache-dir"
+	FlagNameTransitive                               = "transitive"
+	FlagNameOrder                                    = "order"
+	FlagNameJSON                                     = "json"
+	FlagNameFormat                                   = "format"
+	FlagNameRunReport                                = "run-report"
 
 	FlagNameHelp = "help"
 )
@@ -80,6 +106,27 @@ var (
 		FlagNameTerragruntFetchDependencyOutputFromState,
 		FlagNameTerragruntUsePartialParseConfigCache,
 		FlagNameTerragruntIncludeModulePrefix,
+		FlagNameTerragruntReportFile,
+		FlagNameTerragruntReportFormat,
+		FlagNameTerragruntResume,
+		FlagNameTerragruntCheckpointFile,
+		FlagNameTerragruntFailFast,
+		FlagNameTerragruntChangedSince,
+		FlagNameTerragruntTimeout,
+		FlagNameTerragruntConcurrencyLimit,
+		FlagNameTerragruntPlanSummary,
+		FlagNameTerragruntShard,
+		FlagNameTerragruntShardMarkerStore,
+		FlagNameTerragruntShardWaitTimeout,
+		FlagNameTerragruntShardRunID,
+		FlagNameTerragruntDiscoveryCacheFile,
+		FlagNameTerragruntRunAllConfirmEach,
+		FlagNameTerragruntMergeJSON,
+		FlagNameTerragruntRunAllRetryMaxAttempts,
+		FlagNameTerragruntOutputMode,
+		FlagNameTerragruntDiagnosticsFormat,
+		FlagNameTerragruntNoCmdCache,
+		FlagNameTerragruntCmdCacheDir,
 
 		FlagNameHelp,
 	}
@@ -298,6 +345,158 @@ func NewFlags(opts *options.TerragruntOptions) cli.Flags {
 			Destination: &opts.ValidateStrict,
 			Usage:       "Sets strict mode for the validate-inputs command. By default, strict mode is off. When this flag is passed, strict mode is turned on. When strict mode is turned off, the validate-inputs command will only return an error if required inputs are missing from all input sources (env vars, var files, etc). When strict mode is turned on, an error will be returned if required inputs are missing OR if unused variables are passed to Terragrunt.",
 		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntReportFile,
+			Destination: &opts.ReportFile,
+			EnvVar:      "TERRAGRUNT_REPORT_FILE",
+			Usage:       "Write a report with the status, timing and errors of each module to this file when running *-all commands.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntReportFormat,
+			Destination: &opts.ReportFormat,
+			EnvVar:      "TERRAGRUNT_REPORT_FORMAT",
+			Usage:       "The format of the report written with --terragrunt-report-file: json or junit. Default is inferred from the file extension.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntResume,
+			Destination: &opts.Resume,
+			EnvVar:      "TERRAGRUNT_RESUME",
+			Usage:       "Resume a failed run-all apply or destroy, skipping the modules that finished successfully in the previous run.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntCheckpointFile,
+			Destination: &opts.CheckpointFile,
+			EnvVar:      "TERRAGRUNT_CHECKPOINT_FILE",
+			Usage:       "The file where run-all apply and destroy record successful modules for --terragrunt-resume. Default is run-all-checkpoint.json in the download dir.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntFailFast,
+			Destination: &opts.FailFast,
+			EnvVar:      "TERRAGRUNT_FAIL_FAST",
+			Usage:       "*-all commands stop on the first module that fails, skipping modules that haven't started and interrupting running ones.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntChangedSince,
+			Destination: &opts.ChangedSince,
+			EnvVar:      "TERRAGRUNT_CHANGED_SINCE",
+			Usage:       "*-all commands only run the modules affected by the files changed between this git ref and HEAD, and the modules that depend on them.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntTimeout,
+			Destination: &opts.Timeout,
+			EnvVar:      "TERRAGRUNT_TIMEOUT",
+			Usage:       "The maximum time each module may run, e.g. 30m. Terraform is interrupted when it expires. Overrides terraform_timeout in the config.",
+		},
+		&cli.MapFlag[string, int]{
+			Name:        FlagNameTerragruntConcurrencyLimit,
+			Destination: &opts.ConcurrencyLimits,
+			EnvVar:      "TERRAGRUNT_CONCURRENCY_LIMIT",
+			Usage:       "A group=limit pair setting how many modules of a concurrency_group *-all commands may run at once. May be specified multiple times.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntPlanSummary,
+			Destination: &opts.PlanSummary,
+			EnvVar:      "TERRAGRUNT_PLAN_SUMMARY",
+			Usage:       "run-all plan prints a table with the number of resources to add, change, destroy and replace in each module.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntShard,
+			Destination: &opts.Shard,
+			EnvVar:      "TERRAGRUNT_SHARD",
+			Usage:       "Split *-all commands across workers: index/count, e.g. 2/5, runs the second of five shards of the stack.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntShardMarkerStore,
+			Destination: &opts.ShardMarkerStore,
+			EnvVar:      "TERRAGRUNT_SHARD_MARKER_STORE",
+			Usage:       "A folder or S3 URL, shared by all the shards of a run, where the shards record the modules that finished.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntShardWaitTimeout,
+			Destination: &opts.ShardWaitTimeout,
+			EnvVar:      "TERRAGRUNT_SHARD_WAIT_TIMEOUT",
+			Usage:       "The maximum time a shard waits for a module to finish on another shard, e.g. 2h, before the modules that depend on it fail.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntShardRunID,
+			Destination: &opts.ShardRunID,
+			EnvVar:      "TERRAGRUNT_SHARD_RUN_ID",
+			Usage:       "The ID of the run, shared by all its shards, under which the shards record the modules that finished. Defaults to the ID of the CI run.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntDiscoveryCacheFile,
+			Destination: &opts.DiscoveryCacheFile,
+			EnvVar:      "TERRAGRUNT_DISCOVERY_CACHE_FILE",
+			Usage:       "A file in which to cache the module configs parsed to find the stack, so that unchanged configs are not parsed again.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntRunAllConfirmEach,
+			Destination: &opts.RunAllConfirmEach,
+			EnvVar:      "TERRAGRUNT_RUN_ALL_CONFIRM_EACH",
+			Usage:       "run-all apply and destroy plan each module, show the plan and ask whether to apply it, one module at a time.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntMergeJSON,
+			Destination: &opts.MergeOutputJSON,
+			EnvVar:      "TERRAGRUNT_MERGE_JSON",
+			Usage:       "run-all output prints the outputs of all modules as one JSON object keyed by module path relative to the stack.",
+		},
+		&cli.GenericFlag[int]{
+			Name:        FlagNameTerragruntRunAllRetryMaxAttempts,
+			Destination: &opts.RunAllRetryMaxAttempts,
+			EnvVar:      "TERRAGRUNT_RUN_ALL_RETRY_MAX_ATTEMPTS",
+			Usage:       "*-all commands run failed modules, and the modules skipped because of them, again up to N attempts in total.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntOutputMode,
+			Destination: &opts.OutputMode,
+			EnvVar:      "TERRAGRUNT_OUTPUT_MODE",
+			Usage:       "How *-all commands write the output of the modules: stream (default) as it is produced, or buffered as one block per module once it finishes.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntDiagnosticsFormat,
+			Destination: &opts.DiagnosticsFormat,
+			EnvVar:      "TERRAGRUNT_DIAGNOSTICS_FORMAT",
+			Usage:       "The format in which validate-config prints the problems it finds: text (default) or sarif.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntNoCmdCache,
+			Destination: &opts.CmdCache,
+			EnvVar:      "TERRAGRUNT_NO_CMD_CACHE",
+			Usage:       "Don't read or write the on-disk cache of run_cmd calls with --terragrunt-cache-ttl.",
+			Negative:    true,
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntCmdCacheDir,
+			Destination: &opts.CmdCacheDir,
+			EnvVar:      "TERRAGRUNT_CMD_CACHE_DIR",
+			Usage:       "The directory in which the output of run_cmd calls with --terragrunt-cache-ttl is cached.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTransitive,
+			Destination: &opts.GraphTransitive,
+			Usage:       "Include the modules that are connected to the module indirectly, through other modules.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameOrder,
+			Destination: &opts.GraphRunOrder,
+			Usage:       "Print the modules grouped in the order in which run-all would run them.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameJSON,
+			Destination: &opts.GraphJSON,
+			Usage:       "Print JSON instead of plain text.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameFormat,
+			Destination: &opts.GraphFormat,
+			Usage:       "The format of the dependency graph: dot, mermaid or json.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameRunReport,
+			Destination: &opts.GraphRunReport,
+			Usage:       "A JSON run report, written by --terragrunt-report-file, used to colour the modules by the result of their last run.",
+		},
 	}
 
 	sort.Sort(flags)
@@ -50,6 +50,12 @@ const (
 	MetadataRetryableErrors             = "retryable_errors"
 	MetadataRetryMaxAttempts            = "retry_max_attempts"
 	MetadataRetrySleepIntervalSec       = "retry_sleep_interval_sec"
+	MetadataTerraformTimeout            = "terraform_timeout"
+	MetadataConcurrencyGroup            = "concurrency_group"
+	MetadataConcurrencyLimits           = "concurrency_limits"
+	MetadataExclude                     = "exclude"
+	MetadataStackHooks                  = "stack_hook"
+	MetadataInputBlocks                 = "input"
 )
 
 // TerragruntConfig represents a parsed and expanded configuration
@@ -74,6 +80,12 @@ type TerragruntConfig struct {
 	RetryableErrors             []string
 	RetryMaxAttempts            *int
 	RetrySleepIntervalSec       *int
+	TerraformTimeout            *string
+	ConcurrencyGroup            string
+	ConcurrencyLimits           map[string]int
+	Exclude                     *ExcludeConfig
+	StackHooks                  []StackHook
+	InputBlocks                 map[string]InputBlock
 
 	// Fields used for internal tracking
 	// Indicates whether or not this is the result of a partial evaluation
@@ -158,6 +170,16 @@ type terragruntConfigFile struct {
 	RetryableErrors       []string `hcl:"retryable_errors,optional"`
 	RetryMaxAttempts      *int     `hcl:"retry_max_attempts,optional"`
 	RetrySleepIntervalSec *int     `hcl:"retry_sleep_interval_sec,optional"`
+	TerraformTimeout      *string  `hcl:"terraform_timeout,optional"`
+
+	ConcurrencyGroup  *string        `hcl:"concurrency_group,optional"`
+	ConcurrencyLimits map[string]int `hcl:"concurrency_limits,optional"`
+
+	Exclude *ExcludeConfig `hcl:"exclude,block"`
+
+	StackHooks []StackHook `hcl:"stack_hook,block"`
+
+	InputBlocks []terragruntInputBlock `hcl:"input,block"`
 
 	// This struct is used for validating and parsing the entire terragrunt config. Since locals and include are
 	// evaluated in a completely separate cycle, it should not be evaluated here. Otherwise, we can't support self
@@ -303,6 +325,39 @@ const (
 	DeepMergeMapOnly MergeStrategyType = "deep_map_only"
 )
 
+// ExcludeAllActions is the action that matches every command in the actions of an exclude block.
+const ExcludeAllActions = "all"
+
+// ExcludeConfig represents the exclude block, which leaves the module out of run-all commands, for the given actions,
+// when the if condition is true.
+type ExcludeConfig struct {
+	If                  bool     `hcl:"if,attr" cty:"if"`
+	Actions             []string `hcl:"actions,optional" cty:"actions"`
+	ExcludeDependencies bool     `hcl:"exclude_dependencies,optional" cty:"exclude_dependencies"`
+}
+
+// IsExcluded returns true if the module is excluded from run-all commands running the given terraform command. An
+// exclude block without actions excludes the module from all commands.
+func (exclude *ExcludeConfig) IsExcluded(command string) bool {
+	if exclude == nil || !exclude.If {
+		return false
+	}
+	if len(exclude.Actions) == 0 {
+		return true
+	}
+	return util.ListContainsElement(exclude.Actions, ExcludeAllActions) || util.ListContainsElement(exclude.Actions, command)
+}
+
+// StackHook represents the stack_hook block, which runs commands once per run-all invocation: the before commands
+// before the first module of the stack starts, and the after commands after the last one has finished.
+type StackHook struct {
+	Name       string   `hcl:"name,label" cty:"name"`
+	Commands   []string `hcl:"commands,attr" cty:"commands"`
+	Before     []string `hcl:"before,optional" cty:"before"`
+	After      []string `hcl:"after,optional" cty:"after"`
+	WorkingDir *string  `hcl:"working_dir,optional" cty:"working_dir"`
+}
+
 // ModuleDependencies represents the paths to other Terraform modules that must be applied before the current module
 // can be applied
 type ModuleDependencies struct {
@@ -627,10 +682,31 @@ func containsTerragruntModule(path string, info os.FileInfo, terragruntOptions *
 	return util.FileExists(GetDefaultConfigPath(path)), nil
 }
 
-// Read the Terragrunt config file from its default location
+// Read the Terragrunt config file from its default location, and check its inputs against its input blocks
 func ReadTerragruntConfig(terragruntOptions *options.TerragruntOptions) (*TerragruntConfig, error) {
 	terragruntOptions.Logger.Debugf("Reading Terragrunt config file at %s", terragruntOptions.TerragruntConfigPath)
-	return ParseConfigFile(terragruntOptions.TerragruntConfigPath, terragruntOptions, nil, nil)
+
+	configString, err := util.ReadFileAsString(terragruntOptions.TerragruntConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	return parseModuleConfigString(configString, terragruntOptions, terragruntOptions.TerragruntConfigPath, nil)
+}
+
+// Parse the Terragrunt config of a module contained in the given string, like ParseConfigString, and check its inputs,
+// once all the included configs are merged in, against its input blocks. Only the config of the module that is run or
+// validated is checked, as the configs that are read otherwise, such as with read_terragrunt_config, may not set all the
+// inputs they declare.
+func parseModuleConfigString(configString string, terragruntOptions *options.TerragruntOptions, filename string, dependencyOutputs *cty.Value) (*TerragruntConfig, error) {
+	config, file, err := parseConfigString(configString, terragruntOptions, nil, filename, dependencyOutputs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateInputs(config, file); err != nil {
+		return nil, err
+	}
+	return config, nil
 }
 
 // Parse the Terragrunt config file at the given path. If the include parameter is not nil, then treat this as a config
@@ -682,23 +758,35 @@ func ParseConfigString(
 	filename string,
 	dependencyOutputs *cty.Value,
 ) (*TerragruntConfig, error) {
+	config, _, err := parseConfigString(configString, terragruntOptions, includeFromChild, filename, dependencyOutputs)
+	return config, err
+}
+
+// Parse the Terragrunt config contained in the given string like ParseConfigString, and also return the parsed HCL file.
+func parseConfigString(
+	configString string,
+	terragruntOptions *options.TerragruntOptions,
+	includeFromChild *IncludeConfig,
+	filename string,
+	dependencyOutputs *cty.Value,
+) (*TerragruntConfig, *hcl.File, error) {
 	// Parse the HCL string into an AST body that can be decoded multiple times later without having to re-parse
 	parser := hclparse.NewParser()
 	file, err := parseHcl(parser, configString, filename)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Initial evaluation of configuration to load flags like IamRole which will be used for final parsing
 	// https://github.com/gruntwork-io/terragrunt/issues/667
 	if err := setIAMRole(configString, terragruntOptions, includeFromChild, filename); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Decode just the Base blocks. See the function docs for DecodeBaseBlocks for more info on what base blocks are.
 	localsAsCty, trackInclude, err := DecodeBaseBlocks(terragruntOptions, parser, file, filename, includeFromChild, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Initialize evaluation context extensions from base blocks.
@@ -713,7 +801,7 @@ func ParseConfigString(
 		// process.
 		retrievedOutputs, err := decodeAndRetrieveOutputs(file, filename, terragruntOptions, trackInclude, contextExtensions)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		contextExtensions.DecodedDependencies = retrievedOutputs
 	}
@@ -722,22 +810,22 @@ func ParseConfigString(
 	// is appropriate
 	terragruntConfigFile, err := decodeAsTerragruntConfigFile(file, filename, terragruntOptions, contextExtensions)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if terragruntConfigFile == nil {
-		return nil, errors.WithStackTrace(CouldNotResolveTerragruntConfigInFile(filename))
+		return nil, nil, errors.WithStackTrace(CouldNotResolveTerragruntConfigInFile(filename))
 	}
 
 	config, err := convertToTerragruntConfig(terragruntConfigFile, filename, terragruntOptions, contextExtensions)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// If this file includes another, parse and merge it.  Otherwise just return this config.
 	if trackInclude != nil {
 		mergedConfig, err := handleInclude(config, trackInclude, terragruntOptions, contextExtensions.DecodedDependencies)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		// Saving processed includes into configuration, direct assignment since nested includes aren't supported
 		mergedConfig.ProcessedIncludes = trackInclude.CurrentMap
@@ -748,9 +836,10 @@ func ParseConfigString(
 		//   config.
 		mergedConfig.Locals = config.Locals
 
-		return mergedConfig, nil
+		config = mergedConfig
 	}
-	return config, nil
+
+	return config, file, nil
 }
 
 // iamRoleCache - store for cached values of IAM roles
@@ -863,7 +952,7 @@ func convertToTerragruntConfig(
 	}
 
 	if terragruntConfigFromFile.RemoteStateAttr != nil {
-		remoteStateMap, err := parseCtyValueToMap(*terragruntConfigFromFile.RemoteStateAttr)
+		remoteStateMap, err := parseConfigCtyValueToMap(*terragruntConfigFromFile.RemoteStateAttr, terragruntOptions)
 		if err != nil {
 			return nil, err
 		}
@@ -921,6 +1010,40 @@ func convertToTerragruntConfig(
 		terragruntConfig.SetFieldMetadata(MetadataRetrySleepIntervalSec, defaultMetadata)
 	}
 
+	if terragruntConfigFromFile.TerraformTimeout != nil {
+		terragruntConfig.TerraformTimeout = terragruntConfigFromFile.TerraformTimeout
+		terragruntConfig.SetFieldMetadata(MetadataTerraformTimeout, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.ConcurrencyGroup != nil {
+		terragruntConfig.ConcurrencyGroup = *terragruntConfigFromFile.ConcurrencyGroup
+		terragruntConfig.SetFieldMetadata(MetadataConcurrencyGroup, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.ConcurrencyLimits != nil {
+		terragruntConfig.ConcurrencyLimits = terragruntConfigFromFile.ConcurrencyLimits
+		terragruntConfig.SetFieldMetadata(MetadataConcurrencyLimits, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.Exclude != nil {
+		terragruntConfig.Exclude = terragruntConfigFromFile.Exclude
+		terragruntConfig.SetFieldMetadata(MetadataExclude, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.StackHooks != nil {
+		terragruntConfig.StackHooks = terragruntConfigFromFile.StackHooks
+		terragruntConfig.SetFieldMetadata(MetadataStackHooks, defaultMetadata)
+	}
+
+	inputBlocks, err := convertInputBlocks(terragruntConfigFromFile.InputBlocks, configPath, terragruntOptions, contextExtensions)
+	if err != nil {
+		return nil, err
+	}
+	terragruntConfig.InputBlocks = inputBlocks
+	for name := range inputBlocks {
+		terragruntConfig.SetFieldMetadataWithType(MetadataInputBlocks, name, defaultMetadata)
+	}
+
 	if terragruntConfigFromFile.DownloadDir != nil {
 		terragruntConfig.DownloadDir = *terragruntConfigFromFile.DownloadDir
 		terragruntConfig.SetFieldMetadata(MetadataDownloadDir, defaultMetadata)
@@ -965,7 +1088,7 @@ func convertToTerragruntConfig(
 	generateBlocks = append(generateBlocks, terragruntConfigFromFile.GenerateBlocks...)
 
 	if terragruntConfigFromFile.GenerateAttrs != nil {
-		generateMap, err := parseCtyValueToMap(*terragruntConfigFromFile.GenerateAttrs)
+		generateMap, err := parseConfigCtyValueToMap(*terragruntConfigFromFile.GenerateAttrs, terragruntOptions)
 		if err != nil {
 			return nil, err
 		}
@@ -1013,7 +1136,7 @@ func convertToTerragruntConfig(
 	}
 
 	if terragruntConfigFromFile.Inputs != nil {
-		inputs, err := parseCtyValueToMap(*terragruntConfigFromFile.Inputs)
+		inputs, err := parseConfigCtyValueToMap(*terragruntConfigFromFile.Inputs, terragruntOptions)
 		if err != nil {
 			return nil, err
 		}
@@ -27,6 +27,7 @@ func TerragruntConfigAsCty(config *TerragruntConfig) (cty.Value, error) {
 	output[MetadataIamRole] = gostringToCty(config.IamRole)
 	output[MetadataSkip] = goboolToCty(config.Skip)
 	output[MetadataIamAssumeRoleSessionName] = gostringToCty(config.IamAssumeRoleSessionName)
+	output[MetadataConcurrencyGroup] = gostringToCty(config.ConcurrencyGroup)
 
 	terraformConfigCty, err := terraformConfigAsCty(config.Terraform)
 	if err != nil {
@@ -105,6 +106,46 @@ func TerragruntConfigAsCty(config *TerragruntConfig) (cty.Value, error) {
 		output[MetadataRetrySleepIntervalSec] = retrySleepIntervalSecCty
 	}
 
+	terraformTimeoutCty, err := goTypeToCty(config.TerraformTimeout)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if terraformTimeoutCty != cty.NilVal {
+		output[MetadataTerraformTimeout] = terraformTimeoutCty
+	}
+
+	concurrencyLimitsCty, err := goTypeToCty(config.ConcurrencyLimits)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if concurrencyLimitsCty != cty.NilVal {
+		output[MetadataConcurrencyLimits] = concurrencyLimitsCty
+	}
+
+	excludeCty, err := goTypeToCty(config.Exclude)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if excludeCty != cty.NilVal {
+		output[MetadataExclude] = excludeCty
+	}
+
+	stackHooksCty, err := goTypeToCty(config.StackHooks)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if stackHooksCty != cty.NilVal {
+		output[MetadataStackHooks] = stackHooksCty
+	}
+
+	inputBlocksCty, err := inputBlocksAsCty(config.InputBlocks)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if inputBlocksCty != cty.NilVal {
+		output[MetadataInputBlocks] = inputBlocksCty
+	}
+
 	inputsCty, err := convertToCtyWithJson(config.Inputs)
 	if err != nil {
 		return cty.NilVal, err
@@ -176,6 +217,40 @@ func TerragruntConfigAsCtyWithMetadata(config *TerragruntConfig) (cty.Value, err
 	if err := wrapWithMetadata(config, config.RetrySleepIntervalSec, MetadataRetrySleepIntervalSec, &output); err != nil {
 		return cty.NilVal, err
 	}
+	if err := wrapWithMetadata(config, config.TerraformTimeout, MetadataTerraformTimeout, &output); err != nil {
+		return cty.NilVal, err
+	}
+	if err := wrapWithMetadata(config, config.ConcurrencyGroup, MetadataConcurrencyGroup, &output); err != nil {
+		return cty.NilVal, err
+	}
+	if err := wrapWithMetadata(config, config.ConcurrencyLimits, MetadataConcurrencyLimits, &output); err != nil {
+		return cty.NilVal, err
+	}
+	if err := wrapWithMetadata(config, config.Exclude, MetadataExclude, &output); err != nil {
+		return cty.NilVal, err
+	}
+	if err := wrapWithMetadata(config, config.StackHooks, MetadataStackHooks, &output); err != nil {
+		return cty.NilVal, err
+	}
+	if len(config.InputBlocks) > 0 {
+		inputBlocksWithMetadata := map[string]cty.Value{}
+		for name, block := range config.InputBlocks {
+			blockCty, err := inputBlocksAsCty(map[string]InputBlock{name: block})
+			if err != nil {
+				return cty.NilVal, err
+			}
+			content := ValueWithMetadata{Value: blockCty.GetAttr(name)}
+			if metadata, found := config.GetMapFieldMetadata(MetadataInputBlocks, name); found {
+				content.Metadata = metadata
+			}
+			contentCty, err := goTypeToCty(content)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			inputBlocksWithMetadata[name] = contentCty
+		}
+		output[MetadataInputBlocks] = cty.ObjectVal(inputBlocksWithMetadata)
+	}
 
 	// Terraform
 	terraformConfigCty, err := terraformConfigAsCty(config.Terraform)
@@ -99,6 +99,13 @@ func TestTerragruntConfigAsCtyDrift(t *testing.T) {
 }`,
 			},
 		},
+		InputBlocks: map[string]InputBlock{
+			"aws_region": InputBlock{
+				Name:        "aws_region",
+				Type:        cty.String,
+				Description: "The AWS region to deploy to",
+			},
+		},
 	}
 	ctyVal, err := TerragruntConfigAsCty(&testConfig)
 	require.NoError(t, err)
@@ -221,6 +228,18 @@ func terragruntConfigStructFieldToMapKey(t *testing.T, fieldName string) (string
 		return "retry_max_attempts", true
 	case "RetrySleepIntervalSec":
 		return "retry_sleep_interval_sec", true
+	case "TerraformTimeout":
+		return "terraform_timeout", true
+	case "ConcurrencyGroup":
+		return "concurrency_group", true
+	case "ConcurrencyLimits":
+		return "concurrency_limits", true
+	case "Exclude":
+		return "exclude", true
+	case "StackHooks":
+		return "stack_hook", true
+	case "InputBlocks":
+		return "input", true
 	default:
 		t.Fatalf("Unknown struct property: %s", fieldName)
 		// This should not execute
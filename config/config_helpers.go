@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"go.mozilla.org/sops/v3/cmd/sops/formats"
@@ -120,7 +121,7 @@ func CreateTerragruntEvalContext(
 		"path_relative_to_include":                     wrapStringSliceToStringAsFuncImpl(pathRelativeToInclude, extensions.TrackInclude, terragruntOptions),
 		"path_relative_from_include":                   wrapStringSliceToStringAsFuncImpl(pathRelativeFromInclude, extensions.TrackInclude, terragruntOptions),
 		"get_env":                                      wrapStringSliceToStringAsFuncImpl(getEnvironmentVariable, extensions.TrackInclude, terragruntOptions),
-		"run_cmd":                                      wrapStringSliceToStringAsFuncImpl(runCommand, extensions.TrackInclude, terragruntOptions),
+		"run_cmd":                                      wrapSensitiveFunc(wrapStringSliceToStringAsFuncImpl(runCommand, extensions.TrackInclude, terragruntOptions), isQuietRunCmd),
 		"read_terragrunt_config":                       readTerragruntConfigAsFuncImpl(terragruntOptions),
 		"get_platform":                                 wrapVoidToStringAsFuncImpl(getPlatform, extensions.TrackInclude, terragruntOptions),
 		"get_repo_root":                                wrapVoidToStringAsFuncImpl(getRepoRoot, extensions.TrackInclude, terragruntOptions),
@@ -138,7 +139,8 @@ func CreateTerragruntEvalContext(
 		"get_terraform_commands_that_need_locking":     wrapStaticValueToStringSliceAsFuncImpl(TERRAFORM_COMMANDS_NEED_LOCKING),
 		"get_terraform_commands_that_need_input":       wrapStaticValueToStringSliceAsFuncImpl(TERRAFORM_COMMANDS_NEED_INPUT),
 		"get_terraform_commands_that_need_parallelism": wrapStaticValueToStringSliceAsFuncImpl(TERRAFORM_COMMANDS_NEED_PARALLELISM),
-		"sops_decrypt_file":                            wrapStringSliceToStringAsFuncImpl(sopsDecryptFile, extensions.TrackInclude, terragruntOptions),
+		"sops_decrypt_file":                            wrapSensitiveFunc(wrapStringSliceToStringAsFuncImpl(sopsDecryptFile, extensions.TrackInclude, terragruntOptions), alwaysSensitive),
+		"get_secret":                                   wrapSensitiveFunc(wrapStringSliceToStringAsFuncImpl(getSecret, extensions.TrackInclude, terragruntOptions), alwaysSensitive),
 		"get_terragrunt_source_cli_flag":               wrapVoidToStringAsFuncImpl(getTerragruntSourceCliFlag, extensions.TrackInclude, terragruntOptions),
 	}
 
@@ -160,6 +162,9 @@ func CreateTerragruntEvalContext(
 	for k, v := range terraformCompatibilityFunctions {
 		functions[k] = v
 	}
+	// The values passed to sensitive() are registered as secrets to redact as soon as it is called, as only the values
+	// derived from them may be decoded, such as a URL with a sensitive token in it
+	functions["sensitive"] = wrapSensitiveFunc(functions["sensitive"], alwaysSensitive)
 
 	ctx := &hcl.EvalContext{
 		Functions: functions,
@@ -297,20 +302,31 @@ func runCommand(args []string, trackInclude *TrackInclude, terragruntOptions *op
 	suppressOutput := false
 	currentPath := filepath.Dir(terragruntOptions.TerragruntConfigPath)
 	cachePath := currentPath
+	var cacheTTL time.Duration
 
 	checkOptions := true
 	for checkOptions && len(args) > 0 {
-		switch args[0] {
-		case "--terragrunt-quiet":
+		switch {
+		case args[0] == "--terragrunt-quiet":
 			suppressOutput = true
 			args = append(args[:0], args[1:]...)
-		case "--terragrunt-global-cache":
+		case args[0] == "--terragrunt-global-cache":
 			cachePath = "_global_"
 			args = append(args[:0], args[1:]...)
+		case strings.HasPrefix(args[0], runCmdCacheTTLArg+"="):
+			ttl, err := time.ParseDuration(strings.TrimPrefix(args[0], runCmdCacheTTLArg+"="))
+			if err != nil || ttl <= 0 {
+				return "", errors.WithStackTrace(InvalidRunCmdCacheTTL(args[0]))
+			}
+			cacheTTL = ttl
+			args = append(args[:0], args[1:]...)
 		default:
 			checkOptions = false
 		}
 	}
+	if len(args) == 0 {
+		return "", errors.WithStackTrace(EmptyStringNotAllowed("parameter to the run_cmd function"))
+	}
 
 	// To avoid re-run of the same run_cmd command, is used in memory cache for command results, with caching key path + arguments
 	// see: https://github.com/gruntwork-io/terragrunt/issues/1427
@@ -325,6 +341,26 @@ func runCommand(args []string, trackInclude *TrackInclude, terragruntOptions *op
 		return cachedValue, nil
 	}
 
+	// With --terragrunt-cache-ttl, the result is also cached on disk, so that other Terragrunt processes, such as the
+	// ones of the other modules of a run-all, reuse it until the TTL expires. The output of quiet calls and of calls
+	// passed secrets is sensitive, so it is never written to disk.
+	useDiskCache := cacheTTL > 0 && terragruntOptions.CmdCache
+	if useDiskCache && (suppressOutput || util.ContainsSecret(strings.Join(args, " "))) {
+		terragruntOptions.Logger.Debugf("Not caching the sensitive output of run_cmd on disk")
+		useDiskCache = false
+	}
+	if useDiskCache {
+		if cachedValue, foundInCache := readRunCmdCache(terragruntOptions, cacheKey, cacheTTL); foundInCache {
+			if suppressOutput {
+				terragruntOptions.Logger.Debugf("run_cmd, output cached on disk: [REDACTED]")
+			} else {
+				terragruntOptions.Logger.Debugf("run_cmd, output cached on disk: [%s]", cachedValue)
+			}
+			runCommandCache.Put(cacheKey, cachedValue)
+			return cachedValue, nil
+		}
+	}
+
 	cmdOutput, err := shell.RunShellCommandWithOutput(terragruntOptions, currentPath, suppressOutput, false, args[0], args[1:]...)
 	if err != nil {
 		return "", errors.WithStackTrace(err)
@@ -341,6 +377,11 @@ func runCommand(args []string, trackInclude *TrackInclude, terragruntOptions *op
 	// Persisting result in cache to avoid future re-evaluation
 	// see: https://github.com/gruntwork-io/terragrunt/issues/1427
 	runCommandCache.Put(cacheKey, value)
+	if useDiskCache {
+		if err := writeRunCmdCache(terragruntOptions, cacheKey, value); err != nil {
+			terragruntOptions.Logger.Warnf("Failed to cache the output of run_cmd on disk: %v", err)
+		}
+	}
 	return value, nil
 }
 
@@ -522,6 +563,9 @@ func readTerragruntConfig(configPath string, defaultVal *cty.Value, terragruntOp
 	// return an error. If the file does not exist but there is a default val, return the default val. Otherwise,
 	// proceed to parse the file as a terragrunt config file.
 	targetConfig := getCleanedTargetConfigPath(configPath, terragruntOptions.TerragruntConfigPath)
+	if terragruntOptions.FileReadTracker != nil {
+		terragruntOptions.FileReadTracker.Add(targetConfig)
+	}
 	targetConfigFileExists := util.FileExists(targetConfig)
 	if !targetConfigFileExists && defaultVal == nil {
 		return cty.NilVal, errors.WithStackTrace(TerragruntConfigNotFound{Path: targetConfig})
@@ -685,6 +729,9 @@ func sopsDecryptFile(params []string, trackInclude *TrackInclude, terragruntOpti
 	if err != nil {
 		return "", errors.WithStackTrace(err)
 	}
+	if terragruntOptions.FileReadTracker != nil {
+		terragruntOptions.FileReadTracker.Add(canonicalSourceFile)
+	}
 
 	if val, ok := sopsCache.Get(canonicalSourceFile); ok {
 		return val, nil
@@ -875,6 +922,12 @@ func (err EnvVarNotFound) Error() string {
 	return fmt.Sprintf("EnvVarNotFound: Required environment variable %s - not found", err.EnvVar)
 }
 
+type InvalidRunCmdCacheTTL string
+
+func (arg InvalidRunCmdCacheTTL) Error() string {
+	return fmt.Sprintf("Invalid run_cmd argument %s: the TTL must be a positive duration, such as %s=1h", string(arg), runCmdCacheTTLArg)
+}
+
 type EmptyStringNotAllowed string
 
 func (err EmptyStringNotAllowed) Error() string {
@@ -931,3 +984,53 @@ type InvalidIncludeKey struct {
 func (err InvalidIncludeKey) Error() string {
 	return fmt.Sprintf("There is no include block in the current config with the label '%s'", err.name)
 }
+
+type InvalidSecretReference struct {
+	Reference string
+	Reason    string
+}
+
+func (err InvalidSecretReference) Error() string {
+	return fmt.Sprintf("Invalid secret reference %s: %s", err.Reference, err.Reason)
+}
+
+type UnsupportedSecretScheme struct {
+	Reference string
+	Scheme    string
+	Supported []string
+}
+
+func (err UnsupportedSecretScheme) Error() string {
+	return fmt.Sprintf("Unsupported scheme %s in secret reference %s. The supported schemes are: %s", err.Scheme, err.Reference, strings.Join(err.Supported, ", "))
+}
+
+type SecretKeyNotFound struct {
+	Reference string
+	Key       string
+}
+
+func (err SecretKeyNotFound) Error() string {
+	return fmt.Sprintf("The secret of %s has no key %s", err.Reference, err.Key)
+}
+
+type SecretProviderError struct {
+	Reference  string
+	StatusCode int
+	Errors     []string
+}
+
+func (err SecretProviderError) Error() string {
+	if len(err.Errors) == 0 {
+		return fmt.Sprintf("Failed to read the secret %s: status %d", err.Reference, err.StatusCode)
+	}
+	return fmt.Sprintf("Failed to read the secret %s: status %d: %s", err.Reference, err.StatusCode, strings.Join(err.Errors, "; "))
+}
+
+type MissingSecretProviderCredentials struct {
+	Scheme string
+	Detail string
+}
+
+func (err MissingSecretProviderCredentials) Error() string {
+	return fmt.Sprintf("No credentials found to read %s:// secrets: %s", err.Scheme, err.Detail)
+}
@@ -5,10 +5,12 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/pkg/errors"
 	"github.com/gruntwork-io/terragrunt/test/helpers"
+	"github.com/gruntwork-io/terragrunt/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/zclconf/go-cty/cty"
@@ -228,6 +230,83 @@ func TestRunCommand(t *testing.T) {
 	}
 }
 
+func TestRunCommandDiskCache(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		ttl            string
+		cached         bool
+		diskCache      bool
+		expectedOutput string
+	}{
+		{"cached", "1h", true, true, "from-cache"},
+		{"not cached", "1h", false, true, "from-command"},
+		{"expired", "1ns", true, true, "from-command"},
+		{"disabled", "1h", true, false, "from-command"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := terragruntOptionsForTest(t, filepath.Join(t.TempDir(), DefaultTerragruntConfigPath))
+			opts.CmdCacheDir = t.TempDir()
+			opts.CmdCache = testCase.diskCache
+
+			// The cache is keyed by the directory of the config and the command, like the in-memory cache
+			cacheKey := fmt.Sprintf("%v-%v", filepath.Dir(opts.TerragruntConfigPath), []string{"echo", "from-command"})
+			if testCase.cached {
+				require.NoError(t, writeRunCmdCache(opts, cacheKey, "from-cache"))
+			}
+
+			output, err := runCommand([]string{"--terragrunt-cache-ttl=" + testCase.ttl, "echo", "from-command"}, nil, opts)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedOutput, output)
+
+			cachedOutput, isCached := readRunCmdCache(opts, cacheKey, time.Hour)
+			if testCase.diskCache {
+				assert.True(t, isCached)
+				assert.Equal(t, testCase.expectedOutput, cachedOutput)
+			} else {
+				assert.Equal(t, "from-cache", cachedOutput)
+			}
+		})
+	}
+}
+
+func TestRunCommandDiskCacheSkipsSensitiveOutput(t *testing.T) {
+	t.Parallel()
+
+	util.RegisterSecretValue("disk-cache-test-secret")
+
+	for _, args := range [][]string{
+		{"--terragrunt-quiet", "--terragrunt-cache-ttl=1h", "echo", "quiet-output"},
+		{"--terragrunt-cache-ttl=1h", "echo", "disk-cache-test-secret"},
+	} {
+		opts := terragruntOptionsForTest(t, filepath.Join(t.TempDir(), DefaultTerragruntConfigPath))
+		opts.CmdCacheDir = t.TempDir()
+		opts.CmdCache = true
+
+		_, err := runCommand(args, nil, opts)
+		require.NoError(t, err)
+
+		cacheFiles, err := os.ReadDir(opts.CmdCacheDir)
+		require.NoError(t, err)
+		assert.Empty(t, cacheFiles)
+	}
+}
+
+func TestRunCommandInvalidCacheTTL(t *testing.T) {
+	t.Parallel()
+
+	for _, ttl := range []string{"--terragrunt-cache-ttl=1 hour", "--terragrunt-cache-ttl=-1h"} {
+		_, err := runCommand([]string{ttl, "echo", "foo"}, nil, terragruntOptionsForTest(t, DefaultTerragruntConfigPath))
+		assert.IsType(t, InvalidRunCmdCacheTTL(""), errors.Unwrap(err))
+	}
+}
+
 func absPath(t *testing.T, path string) string {
 	out, err := filepath.Abs(path)
 	require.NoError(t, err)
@@ -25,6 +25,9 @@ const (
 	TerragruntFlags
 	TerragruntVersionConstraints
 	RemoteStateBlock
+	TerragruntConcurrency
+	TerragruntExclude
+	StackHooksBlock
 )
 
 // terragruntIncludeMultiple is a struct that can be used to only decode the include block with labels.
@@ -75,6 +78,26 @@ type terragruntVersionConstraints struct {
 	Remain                      hcl.Body `hcl:",remain"`
 }
 
+// terragruntConcurrency is a struct that can be used to only decode the attributes that limit how many modules run
+// concurrently in a stack.
+type terragruntConcurrency struct {
+	ConcurrencyGroup  *string        `hcl:"concurrency_group,optional"`
+	ConcurrencyLimits map[string]int `hcl:"concurrency_limits,optional"`
+	Remain            hcl.Body       `hcl:",remain"`
+}
+
+// terragruntExclude is a struct that can be used to only decode the exclude block in the terragrunt config.
+type terragruntExclude struct {
+	Exclude *ExcludeConfig `hcl:"exclude,block"`
+	Remain  hcl.Body       `hcl:",remain"`
+}
+
+// terragruntStackHooks is a struct that can be used to only decode the stack_hook blocks in the terragrunt config.
+type terragruntStackHooks struct {
+	StackHooks []StackHook `hcl:"stack_hook,block"`
+	Remain     hcl.Body    `hcl:",remain"`
+}
+
 // terragruntDependency is a struct that can be used to only decode the dependency blocks in the terragrunt config
 type terragruntDependency struct {
 	Dependencies []Dependency `hcl:"dependency,block"`
@@ -168,7 +191,8 @@ func TerragruntConfigFromPartialConfigString(
 	filename string,
 	decodeList []PartialDecodeSectionType,
 ) (*TerragruntConfig, error) {
-	if terragruntOptions.UsePartialParseConfigCache {
+	// The cache is bypassed when file reads are tracked, as a cache hit would not call the functions that record them.
+	if terragruntOptions.UsePartialParseConfigCache && terragruntOptions.FileReadTracker == nil {
 		var cacheKey = fmt.Sprintf("%#v-%#v-%#v", configString, includeFromChild, decodeList)
 		var config, found = terragruntConfigCache.Get(cacheKey)
 
@@ -200,6 +224,9 @@ func TerragruntConfigFromPartialConfigString(
 //   - TerragruntVersionConstraints: Parses the attributes related to constraining terragrunt and terraform versions in
 //     the config.
 //   - RemoteStateBlock: Parses the `remote_state` block in the config
+//   - TerragruntConcurrency: Parses the `concurrency_group` and `concurrency_limits` attributes in the config
+//   - TerragruntExclude: Parses the `exclude` block in the config
+//   - StackHooksBlock: Parses the `stack_hook` blocks in the config
 //
 // Note that the following blocks are always decoded:
 // - locals
@@ -343,6 +370,37 @@ func PartialParseConfigString(
 				output.RemoteState = remoteState
 			}
 
+		case TerragruntConcurrency:
+			decoded := terragruntConcurrency{}
+			err := decodeHcl(file, filename, &decoded, terragruntOptions, contextExtensions)
+			if err != nil {
+				return nil, err
+			}
+			if decoded.ConcurrencyGroup != nil {
+				output.ConcurrencyGroup = *decoded.ConcurrencyGroup
+			}
+			if decoded.ConcurrencyLimits != nil {
+				output.ConcurrencyLimits = decoded.ConcurrencyLimits
+			}
+
+		case TerragruntExclude:
+			decoded := terragruntExclude{}
+			err := decodeHcl(file, filename, &decoded, terragruntOptions, contextExtensions)
+			if err != nil {
+				return nil, err
+			}
+			if decoded.Exclude != nil {
+				output.Exclude = decoded.Exclude
+			}
+
+		case StackHooksBlock:
+			decoded := terragruntStackHooks{}
+			err := decodeHcl(file, filename, &decoded, terragruntOptions, contextExtensions)
+			if err != nil {
+				return nil, err
+			}
+			output.StackHooks = decoded.StackHooks
+
 		default:
 			return nil, InvalidPartialBlockName{decode}
 		}
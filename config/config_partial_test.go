@@ -315,3 +315,27 @@ terraform {
 	require.NotNil(t, terragruntConfig.Terraform.Source)
 	assert.Equal(t, *terragruntConfig.Terraform.Source, "../../modules/app")
 }
+
+func TestPartialParseOnlyParsesExclude(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  environment = "prod"
+}
+
+exclude {
+  if      = local.environment == "prod"
+  actions = ["destroy"]
+}
+
+inputs = {
+  vpc_id = dependency.vpc.outputs.vpc_id
+}
+`
+
+	terragruntConfig, err := PartialParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, []PartialDecodeSectionType{TerragruntExclude})
+	require.NoError(t, err)
+
+	assert.Equal(t, &ExcludeConfig{If: true, Actions: []string{"destroy"}}, terragruntConfig.Exclude)
+}
@@ -191,6 +191,104 @@ retryable_errors = [
 	}
 }
 
+func TestParseTerragruntConfigTerraformTimeout(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terraform_timeout = "45m"
+`
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, nil)
+	require.NoError(t, err)
+
+	if assert.NotNil(t, terragruntConfig.TerraformTimeout) {
+		assert.Equal(t, "45m", *terragruntConfig.TerraformTimeout)
+	}
+}
+
+func TestParseTerragruntConfigConcurrencyGroup(t *testing.T) {
+	t.Parallel()
+
+	config := `
+concurrency_group = "networking"
+concurrency_limits = {
+  networking = 1
+  aws-prod   = 2
+}
+`
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "networking", terragruntConfig.ConcurrencyGroup)
+	assert.Equal(t, map[string]int{"networking": 1, "aws-prod": 2}, terragruntConfig.ConcurrencyLimits)
+}
+
+func TestParseTerragruntConfigExclude(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  environment = "prod"
+}
+
+exclude {
+  if                   = local.environment == "prod"
+  actions              = ["apply", "destroy"]
+  exclude_dependencies = true
+}
+`
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, &ExcludeConfig{If: true, Actions: []string{"apply", "destroy"}, ExcludeDependencies: true}, terragruntConfig.Exclude)
+}
+
+func TestParseTerragruntConfigStackHooks(t *testing.T) {
+	t.Parallel()
+
+	config := `
+stack_hook "lock" {
+  commands    = ["apply", "destroy"]
+  before      = ["./lock.sh", "acquire"]
+  after       = ["./lock.sh", "release"]
+  working_dir = "/tmp"
+}
+
+stack_hook "notify" {
+  commands = ["apply"]
+  after    = ["./notify.sh"]
+}
+`
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, nil)
+	require.NoError(t, err)
+
+	workingDir := "/tmp"
+	assert.Equal(t, []StackHook{
+		{Name: "lock", Commands: []string{"apply", "destroy"}, Before: []string{"./lock.sh", "acquire"}, After: []string{"./lock.sh", "release"}, WorkingDir: &workingDir},
+		{Name: "notify", Commands: []string{"apply"}, After: []string{"./notify.sh"}},
+	}, terragruntConfig.StackHooks)
+}
+
+func TestExcludeConfigIsExcluded(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		exclude  *ExcludeConfig
+		command  string
+		expected bool
+	}{
+		{nil, "apply", false},
+		{&ExcludeConfig{If: false, Actions: []string{"apply"}}, "apply", false},
+		{&ExcludeConfig{If: true}, "plan", true},
+		{&ExcludeConfig{If: true, Actions: []string{"all"}}, "plan", true},
+		{&ExcludeConfig{If: true, Actions: []string{"apply", "destroy"}}, "destroy", true},
+		{&ExcludeConfig{If: true, Actions: []string{"apply", "destroy"}}, "plan", false},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, testCase.exclude.IsExcluded(testCase.command), "%v %s", testCase.exclude, testCase.command)
+	}
+}
+
 func TestParseTerragruntJsonConfigRetryConfiguration(t *testing.T) {
 	t.Parallel()
 
@@ -215,6 +215,8 @@ func deepMergeCtyMapsMapOnly(target cty.Value, source cty.Value, opts ...func(*m
 // we convert the given value to JSON using cty's JSON library and then convert the JSON back to a
 // map[string]interface{} using the Go json library.
 func parseCtyValueToMap(value cty.Value) (map[string]interface{}, error) {
+	value = unmarkSensitive(value)
+
 	jsonBytes, err := ctyjson.Marshal(value, cty.DynamicPseudoType)
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
@@ -228,6 +230,25 @@ func parseCtyValueToMap(value cty.Value) (map[string]interface{}, error) {
 	return ctyJsonOutput.Value, nil
 }
 
+// Like parseCtyValueToMap, but if UnknownValuesAsNull is set in the given options, the unknown values in the given value,
+// which can't be converted to JSON, are converted to null first. They come up when validating a config whose
+// dependencies have no mock_outputs.
+func parseConfigCtyValueToMap(value cty.Value, terragruntOptions *options.TerragruntOptions) (map[string]interface{}, error) {
+	if terragruntOptions.UnknownValuesAsNull {
+		var err error
+		value, err = cty.Transform(value, func(_ cty.Path, value cty.Value) (cty.Value, error) {
+			if !value.IsKnown() {
+				return cty.NullVal(value.Type()), nil
+			}
+			return value, nil
+		})
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+	}
+	return parseCtyValueToMap(value)
+}
+
 // When you convert a cty value to JSON, if any of that types are not yet known (i.e., are labeled as
 // DynamicPseudoType), cty's Marshall method will write the type information to a type field and the actual value to
 // a value field. This struct is used to capture that information so when we parse the JSON back into a Go struct, we
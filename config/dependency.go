@@ -429,6 +429,12 @@ func isRenderJsonCommand(terragruntOptions *options.TerragruntOptions) bool {
 	return util.ListContainsElement(terragruntOptions.TerraformCliArgs, renderJsonCommand)
 }
 
+// GetOutputJson returns the outputs of the module with the given config, as printed by `terraform output -json`. The
+// outputs are read, and cached, the same way as the outputs of dependency blocks.
+func GetOutputJson(targetConfig string, terragruntOptions *options.TerragruntOptions) ([]byte, error) {
+	return getOutputJsonWithCaching(targetConfig, terragruntOptions)
+}
+
 // getOutputJsonWithCaching will run terragrunt output on the target config if it is not already cached.
 func getOutputJsonWithCaching(targetConfig string, terragruntOptions *options.TerragruntOptions) ([]byte, error) {
 	// Acquire synchronization lock to ensure only one instance of output is called per config.
@@ -858,6 +864,10 @@ func terraformOutputJsonToCtyValueMap(targetConfig string, jsonBytes []byte) (ma
 		if err != nil {
 			return nil, errors.WithStackTrace(TerragruntOutputParsingError{Path: targetConfig, Err: err})
 		}
+		// The values of sensitive outputs are redacted from the output of Terragrunt, like they are from the one of Terraform
+		if v.Sensitive {
+			registerSensitiveValues(outputVal)
+		}
 		flattenedOutput[k] = outputVal
 	}
 	return flattenedOutput, nil
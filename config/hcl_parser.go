@@ -81,7 +81,8 @@ func decodeHcl(
 		return err
 	}
 
-	decodeDiagnostics := gohcl.DecodeBody(file.Body, evalContext, out)
+	// The marks of sensitive values are removed as they are decoded, since they can't be converted to Go values
+	decodeDiagnostics := gohcl.DecodeBody(sensitiveBody{file.Body}, evalContext, out)
 	if decodeDiagnostics != nil && decodeDiagnostics.HasErrors() {
 		return decodeDiagnostics
 	}
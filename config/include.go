@@ -281,6 +281,26 @@ func (targetConfig *TerragruntConfig) Merge(sourceConfig *TerragruntConfig, terr
 		targetConfig.RetrySleepIntervalSec = sourceConfig.RetrySleepIntervalSec
 	}
 
+	if sourceConfig.TerraformTimeout != nil {
+		targetConfig.TerraformTimeout = sourceConfig.TerraformTimeout
+	}
+
+	if sourceConfig.ConcurrencyGroup != "" {
+		targetConfig.ConcurrencyGroup = sourceConfig.ConcurrencyGroup
+	}
+
+	if sourceConfig.ConcurrencyLimits != nil {
+		targetConfig.ConcurrencyLimits = mergeConcurrencyLimits(sourceConfig.ConcurrencyLimits, targetConfig.ConcurrencyLimits)
+	}
+
+	if sourceConfig.Exclude != nil {
+		targetConfig.Exclude = sourceConfig.Exclude
+	}
+
+	if sourceConfig.StackHooks != nil {
+		mergeStackHooks(terragruntOptions, sourceConfig.StackHooks, &targetConfig.StackHooks)
+	}
+
 	if sourceConfig.TerragruntVersionConstraint != "" {
 		targetConfig.TerragruntVersionConstraint = sourceConfig.TerragruntVersionConstraint
 	}
@@ -330,6 +350,14 @@ func (targetConfig *TerragruntConfig) Merge(sourceConfig *TerragruntConfig, terr
 		targetConfig.GenerateConfigs[key] = val
 	}
 
+	// Input blocks are shallow merged by name, like generate blocks
+	for key, val := range sourceConfig.InputBlocks {
+		if targetConfig.InputBlocks == nil {
+			targetConfig.InputBlocks = map[string]InputBlock{}
+		}
+		targetConfig.InputBlocks[key] = val
+	}
+
 	if sourceConfig.Inputs != nil {
 		targetConfig.Inputs = mergeInputs(sourceConfig.Inputs, targetConfig.Inputs)
 	}
@@ -385,6 +413,26 @@ func (targetConfig *TerragruntConfig) DeepMerge(sourceConfig *TerragruntConfig,
 		targetConfig.RetrySleepIntervalSec = sourceConfig.RetrySleepIntervalSec
 	}
 
+	if sourceConfig.TerraformTimeout != nil {
+		targetConfig.TerraformTimeout = sourceConfig.TerraformTimeout
+	}
+
+	if sourceConfig.ConcurrencyGroup != "" {
+		targetConfig.ConcurrencyGroup = sourceConfig.ConcurrencyGroup
+	}
+
+	if sourceConfig.ConcurrencyLimits != nil {
+		targetConfig.ConcurrencyLimits = mergeConcurrencyLimits(sourceConfig.ConcurrencyLimits, targetConfig.ConcurrencyLimits)
+	}
+
+	if sourceConfig.Exclude != nil {
+		targetConfig.Exclude = sourceConfig.Exclude
+	}
+
+	if sourceConfig.StackHooks != nil {
+		mergeStackHooks(terragruntOptions, sourceConfig.StackHooks, &targetConfig.StackHooks)
+	}
+
 	if sourceConfig.TerragruntVersionConstraint != "" {
 		targetConfig.TerragruntVersionConstraint = sourceConfig.TerragruntVersionConstraint
 	}
@@ -482,6 +530,14 @@ func (targetConfig *TerragruntConfig) DeepMerge(sourceConfig *TerragruntConfig,
 		targetConfig.GenerateConfigs[key] = val
 	}
 
+	// Input blocks are shallow merged by name, like generate blocks
+	for key, val := range sourceConfig.InputBlocks {
+		if targetConfig.InputBlocks == nil {
+			targetConfig.InputBlocks = map[string]InputBlock{}
+		}
+		targetConfig.InputBlocks[key] = val
+	}
+
 	copyFieldsMetadata(sourceConfig, targetConfig)
 	return nil
 }
@@ -591,6 +647,19 @@ func mergeExtraArgs(terragruntOptions *options.TerragruntOptions, childExtraArgs
 	*parentExtraArgs = result
 }
 
+// mergeConcurrencyLimits combines the limits of the parent and the child. The child's limit wins for groups defined in
+// both, so that a child can tighten or relax the limit of a group set in the root config.
+func mergeConcurrencyLimits(childLimits map[string]int, parentLimits map[string]int) map[string]int {
+	out := map[string]int{}
+	for group, limit := range parentLimits {
+		out[group] = limit
+	}
+	for group, limit := range childLimits {
+		out[group] = limit
+	}
+	return out
+}
+
 func mergeInputs(childInputs map[string]interface{}, parentInputs map[string]interface{}) map[string]interface{} {
 	out := map[string]interface{}{}
 
@@ -661,6 +730,28 @@ func mergeErrorHooks(terragruntOptions *options.TerragruntOptions, childHooks []
 	*parentHooks = result
 }
 
+// Merge the stack hooks (stack_hook).
+// Does the same thing as mergeHooks but for stack hooks
+func mergeStackHooks(terragruntOptions *options.TerragruntOptions, childHooks []StackHook, parentHooks *[]StackHook) {
+	result := *parentHooks
+	for _, child := range childHooks {
+		parentHookWithSameName := -1
+		for i, hook := range result {
+			if hook.Name == child.Name {
+				parentHookWithSameName = i
+				break
+			}
+		}
+		if parentHookWithSameName != -1 {
+			terragruntOptions.Logger.Debugf("stack hook '%v' from child overriding parent", child.Name)
+			result[parentHookWithSameName] = child
+		} else {
+			result = append(result, child)
+		}
+	}
+	*parentHooks = result
+}
+
 // getTrackInclude converts the terragrunt include blocks into TrackInclude structs that differentiate between an
 // included config in the current parsing context, and an included config that was passed through from a previous
 // parsing context.
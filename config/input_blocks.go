@@ -0,0 +1,325 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// InputBlock represents an input block, which declares the type of an input, the value it defaults to when it is not
+// set in inputs, and rules to validate its value with, like a Terraform variable block:
+//
+//	input "instance_count" {
+//	  type    = number
+//	  default = 1
+//
+//	  validation {
+//	    condition     = var.instance_count > 0
+//	    error_message = "instance_count must be positive."
+//	  }
+//	}
+type InputBlock struct {
+	Name        string
+	Type        cty.Type
+	Default     *cty.Value
+	Description string
+	Validations []InputValidation
+
+	// The range of the block, to point errors about inputs that are not set in the config being parsed at it
+	DeclRange hcl.Range
+
+	// The defaults of the optional attributes of the type, if any
+	typeDefaults *typeexpr.Defaults
+	// The context of the config that defines the block, to evaluate the validation conditions in
+	evalContext *hcl.EvalContext
+}
+
+// InputValidation represents a validation block of an input block. The condition can reference the value of the input
+// as var.<name>, as well as the locals and functions available in the config that defines the block.
+type InputValidation struct {
+	Condition    hcl.Expression
+	ErrorMessage string
+}
+
+// Struct used to parse input blocks. The body is only decoded when the config is converted, into an inputBlockBody,
+// so that the range of the block is known.
+type terragruntInputBlock struct {
+	Name   string   `hcl:",label"`
+	Remain hcl.Body `hcl:",remain"`
+}
+
+// The body of an input block. The type and the validation conditions are expressions, that are not evaluated when the
+// body is decoded.
+type inputBlockBody struct {
+	Type        hcl.Expression                   `hcl:"type,optional"`
+	Default     *cty.Value                       `hcl:"default,optional"`
+	Description *string                          `hcl:"description,optional"`
+	Validations []terragruntInputValidationBlock `hcl:"validation,block"`
+}
+
+type terragruntInputValidationBlock struct {
+	Condition    hcl.Expression `hcl:"condition,attr"`
+	ErrorMessage string         `hcl:"error_message,attr"`
+}
+
+// Converts the parsed input blocks of the config at the given path to InputBlocks, decoding their bodies and parsing
+// their type constraints.
+func convertInputBlocks(
+	blocks []terragruntInputBlock,
+	configPath string,
+	terragruntOptions *options.TerragruntOptions,
+	contextExtensions EvalContextExtensions,
+) (map[string]InputBlock, error) {
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+
+	evalContext, err := CreateTerragruntEvalContext(configPath, terragruntOptions, contextExtensions)
+	if err != nil {
+		return nil, err
+	}
+
+	var diags hcl.Diagnostics
+	inputBlocks := map[string]InputBlock{}
+	for _, block := range blocks {
+		body := inputBlockBody{}
+		if bodyDiags := gohcl.DecodeBody(block.Remain, evalContext, &body); bodyDiags.HasErrors() {
+			diags = append(diags, bodyDiags...)
+			continue
+		}
+
+		inputBlock := InputBlock{
+			Name:        block.Name,
+			Type:        cty.DynamicPseudoType,
+			Default:     body.Default,
+			DeclRange:   block.Remain.MissingItemRange(),
+			evalContext: evalContext,
+		}
+		if body.Description != nil {
+			inputBlock.Description = *body.Description
+		}
+		for _, validation := range body.Validations {
+			inputBlock.Validations = append(inputBlock.Validations, InputValidation(validation))
+		}
+
+		// gohcl sets the expression of a missing type to one that evaluates to null
+		if value, valueDiags := body.Type.Value(nil); valueDiags.HasErrors() || !value.IsNull() {
+			var typeDiags hcl.Diagnostics
+			inputBlock.Type, inputBlock.typeDefaults, typeDiags = typeexpr.TypeConstraintWithDefaults(body.Type)
+			diags = append(diags, typeDiags...)
+		}
+
+		if _, isDuplicate := inputBlocks[block.Name]; isDuplicate {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate input block",
+				Detail:   fmt.Sprintf("An input block named %q was already defined in this config. The names of input blocks must be unique.", block.Name),
+				Subject:  &inputBlock.DeclRange,
+			})
+		}
+		inputBlocks[block.Name] = inputBlock
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return inputBlocks, nil
+}
+
+// validateInputs checks the inputs of the given fully merged config against its input blocks: the inputs that are not
+// set get the default of their block, and are an error if it has none, and the value of each input must convert to the
+// type of its block and pass all of its validation rules. Validation rules are not checked for null values, such as
+// the outputs of dependencies that are not known yet. The errors are returned as HCL diagnostics that point at the
+// value of the input if it is set in the given file, the file of the config, or at the input block otherwise.
+func validateInputs(config *TerragruntConfig, file *hcl.File) error {
+	if len(config.InputBlocks) == 0 {
+		return nil
+	}
+
+	valueRanges := inputValueRanges(file)
+
+	names := []string{}
+	for name := range config.InputBlocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var diags hcl.Diagnostics
+	for _, name := range names {
+		block := config.InputBlocks[name]
+
+		subject := block.DeclRange
+		var value cty.Value
+		if input, isSet := config.Inputs[name]; isSet {
+			if valueRange, isInFile := valueRanges[name]; isInFile {
+				subject = valueRange
+			}
+
+			var err error
+			if value, err = convertToCtyWithJson(input); err != nil {
+				return err
+			}
+		} else if block.Default != nil {
+			value = *block.Default
+		} else {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing required input",
+				Detail:   fmt.Sprintf("The input %q is not set in inputs, and its input block has no default.", name),
+				Subject:  &subject,
+			})
+			continue
+		}
+
+		if block.typeDefaults != nil {
+			value = block.typeDefaults.Apply(value)
+		}
+		value, err := convert.Convert(value, block.Type)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid value for input",
+				Detail:   fmt.Sprintf("The value of input %q is not a valid %s: %s.", name, typeexpr.TypeString(block.Type), err),
+				Subject:  &subject,
+			})
+			continue
+		}
+
+		if _, isSet := config.Inputs[name]; !isSet {
+			if err := setDefaultInput(config, block, value); err != nil {
+				return err
+			}
+		}
+
+		if value.IsNull() {
+			continue
+		}
+		diags = append(diags, checkInputValidations(block, value, subject)...)
+	}
+
+	if diags.HasErrors() {
+		return diags
+	}
+	return nil
+}
+
+// Evaluates the validation conditions of the given input block with the given value of the input, and returns an error
+// with the given subject for each condition that does not hold.
+func checkInputValidations(block InputBlock, value cty.Value, subject hcl.Range) hcl.Diagnostics {
+	evalContext := block.evalContext.NewChild()
+	evalContext.Variables = map[string]cty.Value{
+		"var": cty.ObjectVal(map[string]cty.Value{block.Name: value}),
+	}
+
+	var diags hcl.Diagnostics
+	for _, validation := range block.Validations {
+		result, conditionDiags := validation.Condition.Value(evalContext)
+		if conditionDiags.HasErrors() {
+			diags = append(diags, conditionDiags...)
+			continue
+		}
+		result = unmarkSensitive(result)
+		if !result.IsWhollyKnown() {
+			continue
+		}
+
+		conditionRange := validation.Condition.Range()
+		result, err := convert.Convert(result, cty.Bool)
+		if err != nil || result.IsNull() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid validation condition",
+				Detail:   "The condition of a validation block must be a bool.",
+				Subject:  &conditionRange,
+			})
+			continue
+		}
+
+		if result.False() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid value for input",
+				Detail:   fmt.Sprintf("%s\n\nThis was checked by the validation rule at %s.", validation.ErrorMessage, conditionRange),
+				Subject:  &subject,
+			})
+		}
+	}
+	return diags
+}
+
+// Sets the input of the given block to the given default value, so that it is passed to Terraform like the inputs that
+// are set.
+func setDefaultInput(config *TerragruntConfig, block InputBlock, value cty.Value) error {
+	inputs, err := parseCtyValueToMap(cty.ObjectVal(map[string]cty.Value{block.Name: value}))
+	if err != nil {
+		return err
+	}
+
+	if config.Inputs == nil {
+		config.Inputs = map[string]interface{}{}
+	}
+	config.Inputs[block.Name] = inputs[block.Name]
+	config.SetFieldMetadataWithType(MetadataInputs, block.Name, map[string]interface{}{foundInFile: block.DeclRange.Filename})
+	return nil
+}
+
+// Returns the ranges of the values of the inputs set in the inputs attribute of the given file, if it is an object
+// literal in the native syntax.
+func inputValueRanges(file *hcl.File) map[string]hcl.Range {
+	ranges := map[string]hcl.Range{}
+
+	content, _, _ := file.Body.PartialContent(&hcl.BodySchema{Attributes: []hcl.AttributeSchema{{Name: MetadataInputs}}})
+	if content == nil {
+		return ranges
+	}
+	attr, hasInputs := content.Attributes[MetadataInputs]
+	if !hasInputs {
+		return ranges
+	}
+	object, isObject := attr.Expr.(*hclsyntax.ObjectConsExpr)
+	if !isObject {
+		return ranges
+	}
+
+	for _, item := range object.Items {
+		key := hcl.ExprAsKeyword(item.KeyExpr)
+		if key == "" {
+			keyValue, diags := item.KeyExpr.Value(nil)
+			if diags.HasErrors() || keyValue.IsNull() || !keyValue.IsKnown() || keyValue.Type() != cty.String {
+				continue
+			}
+			key = keyValue.AsString()
+		}
+		ranges[key] = item.ValueExpr.Range()
+	}
+	return ranges
+}
+
+// Converts the given input blocks to a cty value with the type, default and description of each, keyed by name.
+func inputBlocksAsCty(inputBlocks map[string]InputBlock) (cty.Value, error) {
+	if len(inputBlocks) == 0 {
+		return cty.NilVal, nil
+	}
+
+	output := map[string]cty.Value{}
+	for name, block := range inputBlocks {
+		defaultValue := cty.NullVal(cty.DynamicPseudoType)
+		if block.Default != nil {
+			defaultValue = *block.Default
+		}
+		output[name] = cty.ObjectVal(map[string]cty.Value{
+			"type":        cty.StringVal(typeexpr.TypeString(block.Type)),
+			"default":     defaultValue,
+			"description": cty.StringVal(block.Description),
+		})
+	}
+	return cty.ObjectVal(output), nil
+}
@@ -0,0 +1,229 @@
+package config
+
+import (
+	goerrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestParseTerragruntConfigInputBlocks(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  environments = ["dev", "prod"]
+}
+
+input "environment" {
+  type        = string
+  description = "The environment to deploy to"
+
+  validation {
+    condition     = contains(local.environments, var.environment)
+    error_message = "environment must be dev or prod."
+  }
+}
+
+input "instance_count" {
+  type    = number
+  default = 2
+}
+
+input "tags" {
+  type = object({
+    team  = string
+    owner = optional(string, "platform")
+  })
+}
+
+inputs = {
+  environment = "dev"
+  tags = {
+    team = "web"
+  }
+}
+`
+	terragruntConfig, err := parseModuleConfigString(config, mockOptionsForTest(t), DefaultTerragruntConfigPath, nil)
+	require.NoError(t, err)
+
+	// Inputs that are not set get their default, while the set ones are passed on as they are
+	assert.Equal(t, map[string]interface{}{
+		"environment":    "dev",
+		"instance_count": float64(2),
+		"tags":           map[string]interface{}{"team": "web"},
+	}, terragruntConfig.Inputs)
+
+	require.Len(t, terragruntConfig.InputBlocks, 3)
+	assert.Equal(t, cty.String, terragruntConfig.InputBlocks["environment"].Type)
+	assert.Equal(t, "The environment to deploy to", terragruntConfig.InputBlocks["environment"].Description)
+	assert.Len(t, terragruntConfig.InputBlocks["environment"].Validations, 1)
+}
+
+func TestParseTerragruntConfigInputBlocksErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		config          string
+		expectedSummary string
+		expectedDetail  string
+		expectedLine    int
+	}{
+		{
+			"wrong type",
+			`
+input "instance_count" {
+  type = number
+}
+
+inputs = {
+  instance_count = "two"
+}
+`,
+			"Invalid value for input",
+			`The value of input "instance_count" is not a valid number: a number is required.`,
+			7,
+		},
+		{
+			"failed validation",
+			`
+input "environment" {
+  type = string
+
+  validation {
+    condition     = contains(["dev", "prod"], var.environment)
+    error_message = "environment must be dev or prod."
+  }
+}
+
+inputs = {
+  environment = "staging"
+}
+`,
+			"Invalid value for input",
+			"environment must be dev or prod.\n\nThis was checked by the validation rule at terragrunt.hcl:6,21-63.",
+			12,
+		},
+		{
+			"missing required input",
+			`
+input "environment" {
+  type = string
+}
+`,
+			"Missing required input",
+			`The input "environment" is not set in inputs, and its input block has no default.`,
+			2,
+		},
+		{
+			"invalid type",
+			`
+input "environment" {
+  type = text
+}
+`,
+			"Invalid type specification",
+			`The keyword "text" is not a valid type specification.`,
+			3,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseModuleConfigString(testCase.config, mockOptionsForTest(t), DefaultTerragruntConfigPath, nil)
+			var diags hcl.Diagnostics
+			require.True(t, goerrors.As(err, &diags), "expected HCL diagnostics, got %v", err)
+			require.Len(t, diags, 1)
+			assert.Equal(t, testCase.expectedSummary, diags[0].Summary)
+			assert.Equal(t, testCase.expectedDetail, diags[0].Detail)
+			assert.Equal(t, testCase.expectedLine, diags[0].Subject.Start.Line)
+		})
+	}
+}
+
+func TestParseTerragruntConfigInputBlocksFromParent(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	parentPath := filepath.Join(tmpDir, "root.hcl")
+	childPath := filepath.Join(tmpDir, "child", DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(childPath), os.ModePerm))
+	require.NoError(t, os.WriteFile(parentPath, []byte(`
+input "environment" {
+  type = string
+
+  validation {
+    condition     = length(var.environment) <= 4
+    error_message = "environment must be at most 4 characters long."
+  }
+}
+`), 0644))
+
+	writeChild := func(environment string) {
+		require.NoError(t, os.WriteFile(childPath, []byte(`
+include "root" {
+  path = find_in_parent_folders("root.hcl")
+}
+
+inputs = {
+  environment = "`+environment+`"
+}
+`), 0644))
+	}
+
+	writeChild("prod")
+	terragruntConfig, err := ReadTerragruntConfig(mockOptionsForTestWithConfigPath(t, childPath))
+	require.NoError(t, err)
+	assert.Contains(t, terragruntConfig.InputBlocks, "environment")
+
+	// The error points at the value in the child config, that sets it
+	writeChild("production")
+	_, err = ReadTerragruntConfig(mockOptionsForTestWithConfigPath(t, childPath))
+	var diags hcl.Diagnostics
+	require.True(t, goerrors.As(err, &diags), "expected HCL diagnostics, got %v", err)
+	require.Len(t, diags, 1)
+	assert.Equal(t, childPath, diags[0].Subject.Filename)
+	assert.Equal(t, 7, diags[0].Subject.Start.Line)
+}
+
+func TestParseTerragruntConfigInputBlocksOnlyCheckedForModuleConfig(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	parentPath := filepath.Join(tmpDir, "root.hcl")
+	childPath := filepath.Join(tmpDir, "child", DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(childPath), os.ModePerm))
+	require.NoError(t, os.WriteFile(parentPath, []byte(`
+input "environment" {
+  type = string
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(childPath, []byte(`
+locals {
+  root = read_terragrunt_config(find_in_parent_folders("root.hcl"))
+}
+
+inputs = {
+  environment = "prod"
+}
+`), 0644))
+
+	// The parent config doesn't set the input it declares, which is only an error for the config of a module
+	terragruntConfig, err := ReadTerragruntConfig(mockOptionsForTestWithConfigPath(t, childPath))
+	require.NoError(t, err)
+	assert.Equal(t, "prod", terragruntConfig.Inputs["environment"])
+
+	_, err = ReadTerragruntConfig(mockOptionsForTestWithConfigPath(t, parentPath))
+	var diags hcl.Diagnostics
+	require.True(t, goerrors.As(err, &diags), "expected HCL diagnostics, got %v", err)
+	assert.Equal(t, "Missing required input", diags[0].Summary)
+}
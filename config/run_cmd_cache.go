@@ -0,0 +1,102 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+)
+
+// runCmdCacheTTLArg is the run_cmd argument that caches the output of the command on disk for the given duration, as
+// in run_cmd("--terragrunt-cache-ttl=1h", "git", "describe")
+const runCmdCacheTTLArg = "--terragrunt-cache-ttl"
+
+// runCmdCacheEntry is the output of a run_cmd call as it is cached on disk, in a file of its own
+type runCmdCacheEntry struct {
+	// The key of the call, to guard against hash collisions
+	Key       string    `json:"key"`
+	Output    string    `json:"output"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Returns the directory in which the output of run_cmd calls is cached: the one set with --terragrunt-cmd-cache-dir,
+// or the terragrunt/run_cmd directory of the user cache directory, or of the temp directory if there is none.
+func runCmdCacheDir(terragruntOptions *options.TerragruntOptions) string {
+	if terragruntOptions.CmdCacheDir != "" {
+		return terragruntOptions.CmdCacheDir
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, "terragrunt", "run_cmd")
+}
+
+// Returns the path of the file in which the run_cmd call with the given key is cached.
+func runCmdCachePath(terragruntOptions *options.TerragruntOptions, cacheKey string) string {
+	hash := sha256.Sum256([]byte(cacheKey))
+	return filepath.Join(runCmdCacheDir(terragruntOptions), hex.EncodeToString(hash[:])+".json")
+}
+
+// readRunCmdCache returns the cached output of the run_cmd call with the given key, if it was cached less than the
+// given TTL ago. A cache that can't be read is treated as a miss.
+func readRunCmdCache(terragruntOptions *options.TerragruntOptions, cacheKey string, ttl time.Duration) (string, bool) {
+	cachePath := runCmdCachePath(terragruntOptions, cacheKey)
+
+	contents, err := os.ReadFile(cachePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			terragruntOptions.Logger.Debugf("Failed to read the run_cmd cache %s: %v", cachePath, err)
+		}
+		return "", false
+	}
+
+	var entry runCmdCacheEntry
+	if err := json.Unmarshal(contents, &entry); err != nil {
+		terragruntOptions.Logger.Debugf("Failed to parse the run_cmd cache %s: %v", cachePath, err)
+		return "", false
+	}
+	if entry.Key != cacheKey || time.Since(entry.CreatedAt) > ttl {
+		return "", false
+	}
+	return entry.Output, true
+}
+
+// writeRunCmdCache caches the output of the run_cmd call with the given key on disk. Must not be called with sensitive
+// output, such as the one of calls with --terragrunt-quiet. The file is only readable by the current user, and is
+// written to a temporary file first and renamed, so that concurrent Terragrunt processes never read a partially written
+// cache.
+func writeRunCmdCache(terragruntOptions *options.TerragruntOptions, cacheKey string, output string) error {
+	cachePath := runCmdCachePath(terragruntOptions, cacheKey)
+
+	contents, err := json.Marshal(runCmdCacheEntry{Key: cacheKey, Output: output, CreatedAt: time.Now()})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0700); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	// CreateTemp creates the file with 0600 permissions
+	tmpFile, err := os.CreateTemp(filepath.Dir(cachePath), ".run-cmd-cache-")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(contents); err != nil {
+		tmpFile.Close()
+		return errors.WithStackTrace(err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return errors.WithStackTrace(os.Rename(tmpFile.Name(), cachePath))
+}
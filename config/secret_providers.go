@@ -0,0 +1,203 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+
+	"github.com/gruntwork-io/terragrunt/aws_helper"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+)
+
+// The address Vault listens on by default, used when VAULT_ADDR is not set
+const defaultVaultAddr = "https://127.0.0.1:8200"
+
+// vaultSecretProvider reads secrets from Vault over its HTTP API, at the address in VAULT_ADDR with the token in
+// VAULT_TOKEN, or in the ~/.vault-token file written by vault login. The path of a vault:// reference is the API path of
+// the secret, such as secret/data/app for the app secret of a KV version 2 engine mounted at secret/.
+type vaultSecretProvider struct{}
+
+func (provider *vaultSecretProvider) GetSecret(path string, terragruntOptions *options.TerragruntOptions) (string, error) {
+	addr := terragruntOptions.Env["VAULT_ADDR"]
+	if addr == "" {
+		addr = defaultVaultAddr
+	}
+	token, err := vaultToken(terragruntOptions)
+	if err != nil {
+		return "", err
+	}
+
+	request, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(addr, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	request.Header.Set("X-Vault-Token", token)
+	if namespace := terragruntOptions.Env["VAULT_NAMESPACE"]; namespace != "" {
+		request.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+
+	var secret struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []string               `json:"errors"`
+	}
+	if response.StatusCode != http.StatusOK {
+		// Vault describes the errors in the body, which is not worth failing over if it can't be parsed
+		_ = json.Unmarshal(body, &secret)
+		return "", errors.WithStackTrace(SecretProviderError{Reference: "vault://" + path, StatusCode: response.StatusCode, Errors: secret.Errors})
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+
+	// The secrets of a KV version 2 engine are nested in the data of the response, next to their metadata
+	data := secret.Data
+	if nested, isMap := data["data"].(map[string]interface{}); isMap {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nested
+		}
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return string(encoded), nil
+}
+
+// Returns the Vault token in VAULT_TOKEN, or in the ~/.vault-token file if it is not set.
+func vaultToken(terragruntOptions *options.TerragruntOptions) (string, error) {
+	if token := terragruntOptions.Env["VAULT_TOKEN"]; token != "" {
+		return token, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	token, err := os.ReadFile(filepath.Join(homeDir, ".vault-token"))
+	if os.IsNotExist(err) {
+		return "", errors.WithStackTrace(MissingSecretProviderCredentials{Scheme: "vault", Detail: "set VAULT_TOKEN or log in with vault login"})
+	}
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// awsSecretsManagerProvider reads the current version of secrets from AWS Secrets Manager. The path of an aws-sm://
+// reference is the name or the ARN of the secret. Secrets referenced by name are read from the region of the default
+// AWS config, and the ones referenced by ARN from the region of the ARN.
+type awsSecretsManagerProvider struct{}
+
+func (provider *awsSecretsManagerProvider) GetSecret(path string, terragruntOptions *options.TerragruntOptions) (string, error) {
+	sess, err := aws_helper.CreateAwsSession(nil, terragruntOptions)
+	if err != nil {
+		return "", err
+	}
+
+	config := aws.NewConfig()
+	if secretArn, err := arn.Parse(path); err == nil {
+		config = config.WithRegion(secretArn.Region)
+	}
+
+	output, err := secretsmanager.New(sess, config).GetSecretValue(&secretsmanager.GetSecretValueInput{SecretId: aws.String(path)})
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	if output.SecretString != nil {
+		return *output.SecretString, nil
+	}
+	if utf8.Valid(output.SecretBinary) {
+		return string(output.SecretBinary), nil
+	}
+	return "", errors.WithStackTrace(InvalidSecretReference{Reference: "aws-sm://" + path, Reason: "the secret is not UTF-8 text"})
+}
+
+// ageFileSecretProvider decrypts files encrypted with age, in the binary or the armored format. The identities to
+// decrypt with are read like sops does: from SOPS_AGE_KEY, or from the file in SOPS_AGE_KEY_FILE, or from the
+// sops/age/keys.txt file in the user config dir.
+type ageFileSecretProvider struct{}
+
+func (provider *ageFileSecretProvider) GetSecret(path string, terragruntOptions *options.TerragruntOptions) (string, error) {
+	identities, err := ageIdentities(terragruntOptions)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	defer file.Close()
+
+	var reader io.Reader = bufio.NewReader(file)
+	if header, _ := reader.(*bufio.Reader).Peek(len(armor.Header)); string(header) == armor.Header {
+		reader = armor.NewReader(reader)
+	}
+
+	decrypted, err := age.Decrypt(reader, identities...)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	contents, err := io.ReadAll(decrypted)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	if !utf8.Valid(contents) {
+		return "", errors.WithStackTrace(InvalidSecretReference{Reference: fileSecretSchemePrefix + "age://" + path, Reason: "the decrypted file is not UTF-8 text"})
+	}
+	return string(contents), nil
+}
+
+// Returns the age identities in SOPS_AGE_KEY, or in the file in SOPS_AGE_KEY_FILE, or in the default sops key file.
+func ageIdentities(terragruntOptions *options.TerragruntOptions) ([]age.Identity, error) {
+	if keys := terragruntOptions.Env["SOPS_AGE_KEY"]; keys != "" {
+		identities, err := age.ParseIdentities(strings.NewReader(keys))
+		return identities, errors.WithStackTrace(err)
+	}
+
+	keyFile := terragruntOptions.Env["SOPS_AGE_KEY_FILE"]
+	if keyFile == "" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		keyFile = filepath.Join(configDir, "sops", "age", "keys.txt")
+	}
+
+	file, err := os.Open(keyFile)
+	if os.IsNotExist(err) {
+		return nil, errors.WithStackTrace(MissingSecretProviderCredentials{Scheme: "file+age", Detail: fmt.Sprintf("set SOPS_AGE_KEY or SOPS_AGE_KEY_FILE, or create %s", keyFile)})
+	}
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	defer file.Close()
+
+	identities, err := age.ParseIdentities(file)
+	return identities, errors.WithStackTrace(err)
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// SecretProvider fetches secrets for the get_secret function from a secret store. Each provider handles the references
+// of one URL scheme, such as vault://secret/data/app#password, and is passed the path of the reference, which is
+// secret/data/app in this example. The part after the # selects a key of the secret, and is handled by get_secret, so
+// that a provider only needs to return the whole secret, as a JSON object if it has several keys.
+//
+// The providers of schemes that start with file+ read local files, and are passed the canonical path of the file,
+// relative to the working dir if it was relative.
+type SecretProvider interface {
+	GetSecret(path string, terragruntOptions *options.TerragruntOptions) (string, error)
+}
+
+const fileSecretSchemePrefix = "file+"
+
+var (
+	secretProviders = map[string]SecretProvider{
+		"vault":    &vaultSecretProvider{},
+		"aws-sm":   &awsSecretsManagerProvider{},
+		"file+age": &ageFileSecretProvider{},
+	}
+	secretProvidersMutex sync.RWMutex
+)
+
+// RegisterSecretProvider registers the given provider for the references of the given URL scheme, replacing the
+// provider it had, if any.
+func RegisterSecretProvider(scheme string, provider SecretProvider) {
+	secretProvidersMutex.Lock()
+	defer secretProvidersMutex.Unlock()
+	secretProviders[scheme] = provider
+}
+
+func getSecretProvider(scheme string) (SecretProvider, bool) {
+	secretProvidersMutex.RLock()
+	defer secretProvidersMutex.RUnlock()
+	provider, ok := secretProviders[scheme]
+	return provider, ok
+}
+
+// A cache of the secrets fetched by get_secret, as each fetch is a call to a remote secret store or a decryption. The
+// cache keys are the references of the secrets, with the canonical paths of the files of file+ references.
+var secretCache = NewStringCache()
+
+// getSecret returns the secret with the given reference, of the form <scheme>://<path>[#<key>], from the provider of
+// its scheme. All the string values of the secret are registered as secrets, so that they are redacted from the logs.
+func getSecret(params []string, trackInclude *TrackInclude, terragruntOptions *options.TerragruntOptions) (string, error) {
+	if len(params) != 1 {
+		return "", errors.WithStackTrace(WrongNumberOfParams{Func: "get_secret", Expected: "1", Actual: len(params)})
+	}
+	reference := params[0]
+
+	scheme, path, key, err := parseSecretReference(reference)
+	if err != nil {
+		return "", err
+	}
+	provider, ok := getSecretProvider(scheme)
+	if !ok {
+		return "", errors.WithStackTrace(UnsupportedSecretScheme{Reference: reference, Scheme: scheme, Supported: supportedSecretSchemes()})
+	}
+
+	if strings.HasPrefix(scheme, fileSecretSchemePrefix) {
+		if path, err = util.CanonicalPath(path, terragruntOptions.WorkingDir); err != nil {
+			return "", errors.WithStackTrace(err)
+		}
+		if terragruntOptions.FileReadTracker != nil {
+			terragruntOptions.FileReadTracker.Add(path)
+		}
+	}
+
+	cacheKey := scheme + "://" + path
+	secret, ok := secretCache.Get(cacheKey)
+	if !ok {
+		terragruntOptions.Logger.Debugf("Fetching secret %s://%s", scheme, path)
+		if secret, err = provider.GetSecret(path, terragruntOptions); err != nil {
+			return "", err
+		}
+		registerSecretValues(secret)
+		secretCache.Put(cacheKey, secret)
+	}
+
+	if key == "" {
+		return secret, nil
+	}
+	return selectSecretKey(reference, secret, key)
+}
+
+// Splits the given secret reference into its scheme, path and key.
+func parseSecretReference(reference string) (string, string, string, error) {
+	scheme, rest, hasScheme := strings.Cut(reference, "://")
+	if !hasScheme || scheme == "" {
+		return "", "", "", errors.WithStackTrace(InvalidSecretReference{Reference: reference, Reason: "it has no scheme, such as vault://"})
+	}
+
+	path, key := rest, ""
+	if i := strings.LastIndex(rest, "#"); i >= 0 {
+		path, key = rest[:i], rest[i+1:]
+	}
+	if path == "" {
+		return "", "", "", errors.WithStackTrace(InvalidSecretReference{Reference: reference, Reason: "it has no path"})
+	}
+	return scheme, path, key, nil
+}
+
+// Returns the value of the given key of the given secret, which must be a JSON object. Values that are not strings are
+// returned as JSON.
+func selectSecretKey(reference string, secret string, key string) (string, error) {
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &values); err != nil {
+		return "", errors.WithStackTrace(InvalidSecretReference{Reference: reference, Reason: "the secret is not a JSON object, so it has no keys"})
+	}
+
+	value, ok := values[key]
+	if !ok {
+		return "", errors.WithStackTrace(SecretKeyNotFound{Reference: reference, Key: key})
+	}
+	if str, isString := value.(string); isString {
+		return str, nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return string(encoded), nil
+}
+
+// Registers the given secret as a secret value to redact, as well as all the strings in it if it is JSON, as they can
+// end up in the logs on their own once the secret is decoded.
+func registerSecretValues(secret string) {
+	util.RegisterSecretValue(secret)
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(secret), &decoded); err != nil {
+		return
+	}
+	var register func(value interface{})
+	register = func(value interface{}) {
+		switch value := value.(type) {
+		case string:
+			util.RegisterSecretValue(value)
+		case map[string]interface{}:
+			for _, item := range value {
+				register(item)
+			}
+		case []interface{}:
+			for _, item := range value {
+				register(item)
+			}
+		}
+	}
+	register(decoded)
+}
+
+func supportedSecretSchemes() []string {
+	secretProvidersMutex.RLock()
+	defer secretProvidersMutex.RUnlock()
+
+	schemes := []string{}
+	for scheme := range secretProviders {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// Starts a stand-in for the Vault HTTP API, that serves a KV version 2 secret at secret/data/<name> for the token
+// test-token, and returns the server and the number of requests it got.
+func startTestVaultServer(t *testing.T, name string) (*httptest.Server, *int32) {
+	requests := new(int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Header.Get("X-Vault-Token") != "test-token":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, `{"errors":["permission denied"]}`)
+		case r.URL.Path == "/v1/secret/data/"+name:
+			_, _ = io.WriteString(w, `{"data":{"data":{"username":"admin","password":"vault-test-password","port":5432},"metadata":{"version":3}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"errors":[]}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestGetSecretVault(t *testing.T) {
+	t.Parallel()
+
+	server, requests := startTestVaultServer(t, "vault-test")
+	terragruntOptions := mockOptionsForTest(t)
+	terragruntOptions.Env = map[string]string{"VAULT_ADDR": server.URL, "VAULT_TOKEN": "test-token"}
+
+	password, err := getSecret([]string{"vault://secret/data/vault-test#password"}, nil, terragruntOptions)
+	require.NoError(t, err)
+	assert.Equal(t, "vault-test-password", password)
+
+	port, err := getSecret([]string{"vault://secret/data/vault-test#port"}, nil, terragruntOptions)
+	require.NoError(t, err)
+	assert.Equal(t, "5432", port)
+
+	secret, err := getSecret([]string{"vault://secret/data/vault-test"}, nil, terragruntOptions)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"username":"admin","password":"vault-test-password","port":5432}`, secret)
+
+	// The secret is only fetched once, and its values are redacted from then on
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+	assert.Equal(t, "password: [REDACTED]", util.RedactSecrets("password: vault-test-password"))
+
+	_, err = getSecret([]string{"vault://secret/data/vault-test#token"}, nil, terragruntOptions)
+	assert.True(t, errors.IsError(err, SecretKeyNotFound{Reference: "vault://secret/data/vault-test#token", Key: "token"}))
+}
+
+func TestGetSecretVaultErrors(t *testing.T) {
+	t.Parallel()
+
+	server, _ := startTestVaultServer(t, "vault-errors-test")
+	terragruntOptions := mockOptionsForTest(t)
+	terragruntOptions.Env = map[string]string{"VAULT_ADDR": server.URL, "VAULT_TOKEN": "wrong-token"}
+
+	_, err := getSecret([]string{"vault://secret/data/vault-errors-test#password"}, nil, terragruntOptions)
+	var providerErr SecretProviderError
+	require.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, SecretProviderError{Reference: "vault://secret/data/vault-errors-test", StatusCode: http.StatusForbidden, Errors: []string{"permission denied"}}, providerErr)
+
+	terragruntOptions.Env["VAULT_TOKEN"] = "test-token"
+	_, err = getSecret([]string{"vault://secret/data/missing#password"}, nil, terragruntOptions)
+	require.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, http.StatusNotFound, providerErr.StatusCode)
+}
+
+func TestGetSecretAgeFile(t *testing.T) {
+	t.Parallel()
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	keyFile := filepath.Join(tmpDir, "keys.txt")
+	require.NoError(t, os.WriteFile(keyFile, []byte(identity.String()+"\n"), 0600))
+
+	encrypt := func(path string, contents string, armored bool) {
+		var out bytes.Buffer
+		var dst io.Writer = &out
+		var armorWriter io.WriteCloser
+		if armored {
+			armorWriter = armor.NewWriter(&out)
+			dst = armorWriter
+		}
+		writer, err := age.Encrypt(dst, identity.Recipient())
+		require.NoError(t, err)
+		_, err = io.WriteString(writer, contents)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		if armorWriter != nil {
+			require.NoError(t, armorWriter.Close())
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, path), out.Bytes(), 0644))
+	}
+	encrypt("secret.age", "age-test-password", false)
+	encrypt("secret.json.age", `{"password":"age-test-json-password"}`, true)
+
+	terragruntOptions := mockOptionsForTest(t)
+	terragruntOptions.WorkingDir = tmpDir
+	terragruntOptions.Env = map[string]string{"SOPS_AGE_KEY_FILE": keyFile}
+
+	secret, err := getSecret([]string{"file+age://secret.age"}, nil, terragruntOptions)
+	require.NoError(t, err)
+	assert.Equal(t, "age-test-password", secret)
+
+	secret, err = getSecret([]string{"file+age://" + filepath.Join(tmpDir, "secret.json.age") + "#password"}, nil, terragruntOptions)
+	require.NoError(t, err)
+	assert.Equal(t, "age-test-json-password", secret)
+
+	otherIdentity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	terragruntOptions.Env = map[string]string{"SOPS_AGE_KEY": otherIdentity.String()}
+	encrypt("other.age", "other-password", false)
+	_, err = getSecret([]string{"file+age://other.age"}, nil, terragruntOptions)
+	assert.Error(t, err)
+}
+
+type testSecretProvider struct {
+	secrets map[string]string
+}
+
+func (provider *testSecretProvider) GetSecret(path string, _ *options.TerragruntOptions) (string, error) {
+	return provider.secrets[path], nil
+}
+
+func TestGetSecretInConfig(t *testing.T) {
+	t.Parallel()
+
+	RegisterSecretProvider("test-config", &testSecretProvider{secrets: map[string]string{"db": `{"password":"config-test-password"}`}})
+
+	config := `
+inputs = {
+  password = get_secret("test-config://db#password")
+}
+`
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "config-test-password", terragruntConfig.Inputs["password"])
+}
+
+func TestGetSecretInvalidReferences(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := mockOptionsForTest(t)
+
+	_, err := getSecret([]string{"secret/data/app"}, nil, terragruntOptions)
+	assert.True(t, errors.IsError(err, InvalidSecretReference{Reference: "secret/data/app", Reason: "it has no scheme, such as vault://"}))
+
+	_, err = getSecret([]string{"vault://#password"}, nil, terragruntOptions)
+	assert.True(t, errors.IsError(err, InvalidSecretReference{Reference: "vault://#password", Reason: "it has no path"}))
+
+	_, err = getSecret([]string{"gcp-sm://app"}, nil, terragruntOptions)
+	var unsupported UnsupportedSecretScheme
+	require.ErrorAs(t, err, &unsupported)
+	assert.Equal(t, "gcp-sm", unsupported.Scheme)
+	assert.Contains(t, unsupported.Supported, "vault")
+
+	_, err = getSecret([]string{"vault://a", "vault://b"}, nil, terragruntOptions)
+	assert.True(t, errors.IsError(err, WrongNumberOfParams{Func: "get_secret", Expected: "1", Actual: 2}))
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// sensitiveMark marks the values that must not be shown. It is the mark set by the sensitive() function of Terraform,
+// which is also set on the secrets returned by get_secret and sops_decrypt_file, and on the output of run_cmd with
+// --terragrunt-quiet. The mark flows to all the values derived from the marked ones within the config, such as
+// base64encode(local.password), and is removed when the values are decoded, at which point their strings are registered
+// as secrets to redact from all the output of Terragrunt.
+const sensitiveMark = "sensitive"
+
+// Returns a function that calls the given one, and marks its result as sensitive when isSensitive is true for its
+// arguments.
+func wrapSensitiveFunc(fn function.Function, isSensitive func(args []cty.Value) bool) function.Function {
+	return function.New(&function.Spec{
+		Params:   fn.Params(),
+		VarParam: fn.VarParam(),
+		Type:     fn.ReturnTypeForValues,
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			result, err := fn.Call(args)
+			if err != nil || !isSensitive(args) {
+				return result, err
+			}
+			registerSensitiveValues(result)
+			return result.Mark(sensitiveMark), nil
+		},
+	})
+}
+
+// Returns true for the arguments of run_cmd calls with the --terragrunt-quiet option, whose output is sensitive.
+func isQuietRunCmd(args []cty.Value) bool {
+	for _, arg := range args {
+		if !arg.IsKnown() || arg.IsNull() || arg.Type() != cty.String {
+			return false
+		}
+		param := arg.AsString()
+		if param == "--terragrunt-quiet" {
+			return true
+		}
+		if len(param) < 2 || param[:2] != "--" {
+			return false
+		}
+	}
+	return false
+}
+
+func alwaysSensitive(_ []cty.Value) bool {
+	return true
+}
+
+// unmarkSensitive returns the given value without marks, after registering the strings of its sensitive parts as secrets
+// to redact, as marked values can't be converted to Go values or JSON.
+func unmarkSensitive(value cty.Value) cty.Value {
+	if !value.ContainsMarked() {
+		return value
+	}
+
+	unmarked, pathMarks := value.UnmarkDeepWithPaths()
+	for _, pathMark := range pathMarks {
+		if _, isSensitive := pathMark.Marks[sensitiveMark]; !isSensitive {
+			continue
+		}
+		if sensitiveValue, err := pathMark.Path.Apply(unmarked); err == nil {
+			registerSensitiveValues(sensitiveValue)
+		}
+	}
+	return unmarked
+}
+
+// Registers all the known strings in the given value as secrets to redact.
+func registerSensitiveValues(value cty.Value) {
+	value, _ = value.UnmarkDeep()
+	_ = cty.Walk(value, func(_ cty.Path, value cty.Value) (bool, error) {
+		if value.IsKnown() && !value.IsNull() && value.Type() == cty.String {
+			util.RegisterSecretValue(value.AsString())
+		}
+		return true, nil
+	})
+}
+
+// sensitiveBody is an hcl.Body whose attributes evaluate to values without marks, so that the values of configs can be
+// decoded. See unmarkSensitive.
+type sensitiveBody struct {
+	hcl.Body
+}
+
+func (body sensitiveBody) Content(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Diagnostics) {
+	content, diags := body.Body.Content(schema)
+	return unmarkingContent(content), diags
+}
+
+func (body sensitiveBody) PartialContent(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Body, hcl.Diagnostics) {
+	content, remain, diags := body.Body.PartialContent(schema)
+	if remain != nil {
+		remain = sensitiveBody{remain}
+	}
+	return unmarkingContent(content), remain, diags
+}
+
+func (body sensitiveBody) JustAttributes() (hcl.Attributes, hcl.Diagnostics) {
+	attrs, diags := body.Body.JustAttributes()
+	return unmarkingAttributes(attrs), diags
+}
+
+func unmarkingContent(content *hcl.BodyContent) *hcl.BodyContent {
+	if content == nil {
+		return nil
+	}
+
+	unmarking := *content
+	unmarking.Attributes = unmarkingAttributes(content.Attributes)
+	unmarking.Blocks = make(hcl.Blocks, len(content.Blocks))
+	for i, block := range content.Blocks {
+		unmarkingBlock := *block
+		unmarkingBlock.Body = sensitiveBody{block.Body}
+		unmarking.Blocks[i] = &unmarkingBlock
+	}
+	return &unmarking
+}
+
+func unmarkingAttributes(attrs hcl.Attributes) hcl.Attributes {
+	if attrs == nil {
+		return nil
+	}
+
+	unmarking := make(hcl.Attributes, len(attrs))
+	for name, attr := range attrs {
+		unmarkingAttr := *attr
+		unmarkingAttr.Expr = sensitiveExpression{attr.Expr}
+		unmarking[name] = &unmarkingAttr
+	}
+	return unmarking
+}
+
+// sensitiveExpression is an hcl.Expression that evaluates to the value of the wrapped one without marks. See
+// unmarkSensitive.
+type sensitiveExpression struct {
+	hcl.Expression
+}
+
+func (expr sensitiveExpression) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	value, diags := expr.Expression.Value(ctx)
+	return unmarkSensitive(value), diags
+}
+
+// UnwrapExpression returns the wrapped expression, for the functions of the hcl package that handle expressions of
+// specific types without evaluating them, such as hcl.AbsTraversalForExpr.
+func (expr sensitiveExpression) UnwrapExpression() hcl.Expression {
+	return expr.Expression
+}
+
+// ExprList returns the elements of the wrapped static list expression, which also evaluate to values without marks, for
+// hcl.ExprList. Returns nil if the wrapped expression is not a static list.
+func (expr sensitiveExpression) ExprList() []hcl.Expression {
+	elements, diags := hcl.ExprList(expr.Expression)
+	if diags.HasErrors() {
+		return nil
+	}
+
+	unmarking := make([]hcl.Expression, len(elements))
+	for i, element := range elements {
+		unmarking[i] = sensitiveExpression{element}
+	}
+	return unmarking
+}
+
+// ExprMap returns the pairs of the wrapped static map expression, whose keys and values also evaluate to values without
+// marks, for hcl.ExprMap. Returns nil if the wrapped expression is not a static map.
+func (expr sensitiveExpression) ExprMap() []hcl.KeyValuePair {
+	pairs, diags := hcl.ExprMap(expr.Expression)
+	if diags.HasErrors() {
+		return nil
+	}
+
+	unmarking := make([]hcl.KeyValuePair, len(pairs))
+	for i, pair := range pairs {
+		unmarking[i] = hcl.KeyValuePair{Key: sensitiveExpression{pair.Key}, Value: sensitiveExpression{pair.Value}}
+	}
+	return unmarking
+}
+
+// ExprCall returns the wrapped static function call, whose arguments also evaluate to values without marks, for
+// hcl.ExprCall. Returns nil if the wrapped expression is not a static function call.
+func (expr sensitiveExpression) ExprCall() *hcl.StaticCall {
+	call, diags := hcl.ExprCall(expr.Expression)
+	if diags.HasErrors() {
+		return nil
+	}
+
+	unmarking := *call
+	unmarking.Arguments = make([]hcl.Expression, len(call.Arguments))
+	for i, arg := range call.Arguments {
+		unmarking.Arguments[i] = sensitiveExpression{arg}
+	}
+	return &unmarking
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+func TestParseTerragruntConfigSensitiveValues(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  password = sensitive("sensitive-test-password")
+  encoded  = base64encode(local.password)
+}
+
+input "password" {
+  type = string
+
+  validation {
+    condition     = length(var.password) > 8
+    error_message = "password must be longer than 8 characters."
+  }
+}
+
+terraform {
+  source = "git::https://example.com/${sensitive("sensitive-test-token")}/modules.git"
+}
+
+inputs = {
+  password = local.password
+  url      = "postgres://admin:${local.password}@db"
+  encoded  = local.encoded
+  exposed  = nonsensitive(sensitive("sensitive-test-exposed"))
+  quiet    = run_cmd("--terragrunt-quiet", "echo", "sensitive-test-quiet-output")
+  loud     = run_cmd("echo", "sensitive-test-loud-output")
+}
+`
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, nil)
+	require.NoError(t, err)
+
+	// The values themselves are passed on as they are
+	encoded := base64.StdEncoding.EncodeToString([]byte("sensitive-test-password"))
+	assert.Equal(t, "sensitive-test-password", terragruntConfig.Inputs["password"])
+	assert.Equal(t, encoded, terragruntConfig.Inputs["encoded"])
+	assert.Equal(t, "sensitive-test-exposed", terragruntConfig.Inputs["exposed"])
+	assert.Equal(t, "git::https://example.com/sensitive-test-token/modules.git", *terragruntConfig.Terraform.Source)
+
+	// But the sensitive ones, and the ones derived from them, are redacted from the output
+	assert.Equal(t, "[REDACTED] [REDACTED] [REDACTED] [REDACTED]", util.RedactSecrets("sensitive-test-password "+encoded+" sensitive-test-token sensitive-test-quiet-output"))
+	assert.Equal(t, "sensitive-test-loud-output", util.RedactSecrets("sensitive-test-loud-output"))
+}
+
+func TestTerraformOutputJsonToCtyValueMapSensitive(t *testing.T) {
+	t.Parallel()
+
+	outputs, err := terraformOutputJsonToCtyValueMap("vpc", []byte(`{
+  "db_password": {"sensitive": true, "type": "string", "value": "sensitive-test-output"},
+  "vpc_id": {"sensitive": false, "type": "string", "value": "vpc-sensitive-test"}
+}`))
+	require.NoError(t, err)
+	assert.Equal(t, "sensitive-test-output", outputs["db_password"].AsString())
+
+	assert.Equal(t, "[REDACTED] vpc-sensitive-test", util.RedactSecrets("sensitive-test-output vpc-sensitive-test"))
+}
+
+func TestSensitiveExpressionStaticElements(t *testing.T) {
+	t.Parallel()
+
+	file, diags := hclsyntax.ParseConfig([]byte(`
+list = [local.password, "visible"]
+map  = { password = local.password }
+call = upper(local.password)
+`), "test.hcl", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+	attrs, diags := sensitiveBody{file.Body}.JustAttributes()
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	ctx := &hcl.EvalContext{Variables: map[string]cty.Value{
+		"local": cty.ObjectVal(map[string]cty.Value{"password": cty.StringVal("sensitive-test-static").Mark(sensitiveMark)}),
+	}}
+
+	elements, diags := hcl.ExprList(attrs["list"].Expr)
+	require.False(t, diags.HasErrors(), diags.Error())
+	var password string
+	require.False(t, gohcl.DecodeExpression(elements[0], ctx, &password).HasErrors())
+	assert.Equal(t, "sensitive-test-static", password)
+
+	pairs, diags := hcl.ExprMap(attrs["map"].Expr)
+	require.False(t, diags.HasErrors(), diags.Error())
+	value, diags := pairs[0].Value.Value(ctx)
+	require.False(t, diags.HasErrors(), diags.Error())
+	assert.False(t, value.IsMarked())
+
+	call, diags := hcl.ExprCall(attrs["call"].Expr)
+	require.False(t, diags.HasErrors(), diags.Error())
+	assert.Equal(t, "upper", call.Name)
+	value, diags = call.Arguments[0].Value(ctx)
+	require.False(t, diags.HasErrors(), diags.Error())
+	assert.False(t, value.IsMarked())
+
+	_, diags = hcl.ExprList(attrs["call"].Expr)
+	assert.True(t, diags.HasErrors())
+}
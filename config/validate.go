@@ -0,0 +1,162 @@
+package config
+
+import (
+	goerrors "errors"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The block types whose labels must be unique within a config
+var uniquelyNamedBlockTypes = []string{MetadataDependency, MetadataGenerateConfigs, "include", MetadataStackHooks, MetadataInputBlocks}
+
+// ValidateConfigFile parses the Terragrunt config at the given path, and the configs it includes, the same way as when
+// the module is run, but without running Terraform: the outputs of the dependency blocks are replaced with their
+// mock_outputs, or with unknown values if they have none, which are passed on as null. Returns every problem found as
+// HCL diagnostics, with the file, line and column of the problem where known: syntax errors, unknown or invalid
+// attributes and blocks, blocks with duplicate names and generate blocks with invalid settings. Problems that HCL does
+// not report with a position are returned as diagnostics whose subject is the whole file.
+func ValidateConfigFile(filename string, terragruntOptions *options.TerragruntOptions) hcl.Diagnostics {
+	configString, err := util.ReadFileAsString(filename)
+	if err != nil {
+		return errorToDiagnostics(filename, err)
+	}
+
+	file, err := parseHcl(hclparse.NewParser(), configString, filename)
+	if err != nil {
+		return errorToDiagnostics(filename, err)
+	}
+
+	diags := validateBlocks(file)
+
+	terragruntOptions = terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+	terragruntOptions.UnknownValuesAsNull = true
+
+	dependencyOutputs, err := mockDependencyOutputs(filename, terragruntOptions)
+	if err == nil {
+		_, err = parseModuleConfigString(configString, terragruntOptions, filename, dependencyOutputs)
+	}
+	if err != nil {
+		// The problems found statically make the parsing fail too, but with a less precise error, which is dropped
+		if _, isHCLError := hclDiagnostics(err); isHCLError || !diags.HasErrors() {
+			diags = append(diags, errorToDiagnostics(filename, err)...)
+		}
+	}
+	return diags
+}
+
+// Statically checks the blocks of the given file for problems that decoding does not report with a position: blocks
+// that must be uniquely named but reuse a name, and generate blocks with a literal if_exists that is not valid. Files in
+// the JSON syntax are not checked.
+func validateBlocks(file *hcl.File) hcl.Diagnostics {
+	body, isNativeSyntax := file.Body.(*hclsyntax.Body)
+	if !isNativeSyntax {
+		return nil
+	}
+
+	var diags hcl.Diagnostics
+	seen := map[string]*hclsyntax.Block{}
+	for _, block := range body.Blocks {
+		if util.ListContainsElement(uniquelyNamedBlockTypes, block.Type) {
+			name := ""
+			subject := block.DefRange()
+			if len(block.Labels) > 0 {
+				name = block.Labels[0]
+				subject = block.LabelRanges[0]
+			}
+
+			key := block.Type + "." + name
+			if previous, isDuplicate := seen[key]; isDuplicate {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("Duplicate %s block", block.Type),
+					Detail:   fmt.Sprintf("A %s block named %q was already defined at %s. The names of %s blocks must be unique.", block.Type, name, previous.DefRange(), block.Type),
+					Subject:  &subject,
+				})
+			} else {
+				seen[key] = block
+			}
+		}
+
+		if block.Type == MetadataGenerateConfigs {
+			diags = append(diags, validateGenerateBlock(block)...)
+		}
+	}
+	return diags
+}
+
+// Checks the if_exists attribute of the given generate block, if it is set to a literal value.
+func validateGenerateBlock(block *hclsyntax.Block) hcl.Diagnostics {
+	attr, hasIfExists := block.Body.Attributes["if_exists"]
+	if !hasIfExists {
+		return nil
+	}
+
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() || value.IsNull() || value.Type() != cty.String {
+		// Not a literal, so it is checked when the config is decoded
+		return nil
+	}
+
+	if _, err := codegen.GenerateConfigExistsFromString(value.AsString()); err != nil {
+		subject := attr.Expr.Range()
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid generate block",
+			Detail:   fmt.Sprintf("%s. Valid values are %s, %s, %s and %s.", err, codegen.ExistsErrorStr, codegen.ExistsSkipStr, codegen.ExistsOverwriteStr, codegen.ExistsOverwriteTerragruntStr),
+			Subject:  &subject,
+		}}
+	}
+	return nil
+}
+
+// Returns the value of the dependency variable for the config at the given path when its dependency outputs are mocked:
+// each dependency block, including those of included configs, gets its mock_outputs as its outputs, or an unknown value
+// if it has no mock_outputs, so that any reference to its outputs is accepted.
+func mockDependencyOutputs(filename string, terragruntOptions *options.TerragruntOptions) (*cty.Value, error) {
+	config, err := PartialParseConfigFile(filename, terragruntOptions, nil, []PartialDecodeSectionType{DependencyBlock})
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies := map[string]cty.Value{}
+	for _, dependency := range config.TerragruntDependencies {
+		outputs := cty.DynamicVal
+		if dependency.MockOutputs != nil {
+			outputs = *dependency.MockOutputs
+		}
+		dependencies[dependency.Name] = cty.ObjectVal(map[string]cty.Value{"outputs": outputs})
+	}
+
+	value := cty.ObjectVal(dependencies)
+	return &value, nil
+}
+
+// Returns the HCL diagnostics wrapped in the given error, and whether it wraps any.
+func hclDiagnostics(err error) (hcl.Diagnostics, bool) {
+	var diags hcl.Diagnostics
+	isHCLError := goerrors.As(err, &diags)
+	return diags, isHCLError
+}
+
+// Returns the HCL diagnostics wrapped in the given error, or a diagnostic with the message of the error and the given
+// file as subject if the error has none.
+func errorToDiagnostics(filename string, err error) hcl.Diagnostics {
+	if diags, isHCLError := hclDiagnostics(err); isHCLError {
+		return diags
+	}
+
+	return hcl.Diagnostics{{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid Terragrunt config",
+		Detail:   err.Error(),
+		Subject:  &hcl.Range{Filename: filename},
+	}}
+}
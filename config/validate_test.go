@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestValidateConfigFile(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		config          string
+		expectedSummary string
+		expectedLine    int
+	}{
+		{
+			"valid with mocked dependency outputs",
+			`
+dependency "vpc" {
+  config_path = "../vpc"
+}
+
+dependency "db" {
+  config_path = "../db"
+  mock_outputs = {
+    address = "db.local"
+  }
+}
+
+inputs = {
+  vpc_id     = dependency.vpc.outputs.id
+  db_address = dependency.db.outputs.address
+}
+`,
+			"",
+			0,
+		},
+		{
+			"syntax error",
+			`
+inputs = {
+  foo =
+}
+`,
+			"Invalid expression",
+			3,
+		},
+		{
+			"unknown attribute",
+			`
+terraform {
+  source = "../modules/app"
+}
+
+input = {
+  foo = "bar"
+}
+`,
+			"Unsupported argument",
+			6,
+		},
+		{
+			"duplicate dependency",
+			`
+dependency "vpc" {
+  config_path = "../vpc"
+}
+
+dependency "vpc" {
+  config_path = "../other-vpc"
+}
+`,
+			"Duplicate dependency block",
+			6,
+		},
+		{
+			"invalid generate block",
+			`
+generate "provider" {
+  path      = "provider.tf"
+  if_exists = "replace"
+  contents  = ""
+}
+`,
+			"Invalid generate block",
+			4,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			configPath := filepath.Join(t.TempDir(), "app", DefaultTerragruntConfigPath)
+			require.NoError(t, os.MkdirAll(filepath.Dir(configPath), os.ModePerm))
+			require.NoError(t, os.WriteFile(configPath, []byte(testCase.config), 0644))
+			terragruntOptions, err := options.NewTerragruntOptionsForTest(configPath)
+			require.NoError(t, err)
+
+			diags := ValidateConfigFile(configPath, terragruntOptions)
+			if testCase.expectedSummary == "" {
+				assert.Empty(t, diags)
+				return
+			}
+
+			require.True(t, diags.HasErrors())
+			assert.Equal(t, hcl.DiagError, diags[0].Severity)
+			assert.Equal(t, testCase.expectedSummary, diags[0].Summary)
+			require.NotNil(t, diags[0].Subject)
+			assert.Equal(t, configPath, diags[0].Subject.Filename)
+			assert.Equal(t, testCase.expectedLine, diags[0].Subject.Start.Line)
+		})
+	}
+}
+
+func TestParseConfigStringFailsOnUnknownValues(t *testing.T) {
+	t.Parallel()
+
+	config := `
+inputs = {
+  vpc_id = dependency.vpc.outputs.id
+}
+`
+	dependencyOutputs := cty.ObjectVal(map[string]cty.Value{
+		"vpc": cty.ObjectVal(map[string]cty.Value{"outputs": cty.DynamicVal}),
+	})
+
+	// Outside of validate-config, unknown values are not passed on to Terraform as null
+	_, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &dependencyOutputs)
+	require.Error(t, err)
+
+	terragruntOptions := mockOptionsForTest(t)
+	terragruntOptions.UnknownValuesAsNull = true
+	terragruntConfig, err := ParseConfigString(config, terragruntOptions, nil, DefaultTerragruntConfigPath, &dependencyOutputs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"vpc_id": nil}, terragruntConfig.Inputs)
+}
@@ -0,0 +1,102 @@
+package configstack
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	// OutputModeStream writes the output of the modules of a run-all as it is produced, so that the output of modules
+	// that run in parallel is interleaved.
+	OutputModeStream = "stream"
+
+	// OutputModeBuffered holds back the output of each module of a run-all until the module finishes, and then writes it
+	// as one block.
+	OutputModeBuffered = "buffered"
+)
+
+// prepareBufferedOutput makes every module of the stack write its stdout and stderr into buffers of its own, instead of
+// the writers of its options, and flushes them to those writers as one block, headed by the path of the module, each
+// time the module finishes running. The blocks of different modules are never interleaved, and show up in the order in
+// which the modules finish.
+func (stack *Stack) prepareBufferedOutput() {
+	var flushMutex sync.Mutex
+
+	for _, module := range stack.Modules {
+		runTerragrunt := module.TerragruntOptions.RunTerragrunt
+		modulePath := module.Path
+		writer := module.TerragruntOptions.Writer
+		errWriter := module.TerragruntOptions.ErrWriter
+
+		var stdout, stderr bytes.Buffer
+		module.TerragruntOptions.Writer = &stdout
+		module.TerragruntOptions.ErrWriter = &stderr
+
+		module.TerragruntOptions.RunTerragrunt = func(opts *options.TerragruntOptions) error {
+			runErr := runTerragrunt(opts)
+
+			flushMutex.Lock()
+			defer flushMutex.Unlock()
+			if err := flushModuleOutput(writer, errWriter, modulePath, &stdout, &stderr); err != nil {
+				opts.Logger.Errorf("Failed to write the output of module %s: %v", modulePath, err)
+			}
+			return runErr
+		}
+	}
+}
+
+// Returns why terraform may prompt for input in the modules of a run-all with the given options, or an empty string if it
+// never prompts. Buffered output would hold the prompts back until the modules finish, so the run would hang.
+func promptReason(terragruntOptions *options.TerragruntOptions) string {
+	args := terragruntOptions.TerraformCliArgs
+	if util.ListContainsElement(args, "-input=true") {
+		return "-input=true"
+	}
+
+	stackCmd := terragruntOptions.TerraformCommand
+	if (stackCmd == "apply" || stackCmd == "destroy") && !util.ListContainsElement(args, "-auto-approve") {
+		return fmt.Sprintf("%s without -auto-approve", stackCmd)
+	}
+	return ""
+}
+
+// Writes a header with the given module path and the buffered stderr to errWriter, and the buffered stdout to writer,
+// and empties the buffers, so that the next run of the module starts with empty buffers.
+func flushModuleOutput(writer io.Writer, errWriter io.Writer, modulePath string, stdout *bytes.Buffer, stderr *bytes.Buffer) error {
+	defer stdout.Reset()
+	defer stderr.Reset()
+
+	if stdout.Len() == 0 && stderr.Len() == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(errWriter, "\nOutput of module %s:\n\n", modulePath); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if _, err := stdout.WriteTo(writer); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if _, err := stderr.WriteTo(errWriter); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// Custom error types
+
+type UnsupportedOutputMode string
+
+func (mode UnsupportedOutputMode) Error() string {
+	return fmt.Sprintf("Unsupported output mode %q. Supported modes are: %s, %s", string(mode), OutputModeStream, OutputModeBuffered)
+}
+
+type BufferedOutputWithPrompts string
+
+func (reason BufferedOutputWithPrompts) Error() string {
+	return fmt.Sprintf("--terragrunt-output-mode %s can't be used when terraform may prompt for input (%s), as the prompts would be held back until the modules finish. Use --terragrunt-output-mode %s instead.", OutputModeBuffered, string(reason), OutputModeStream)
+}
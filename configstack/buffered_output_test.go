@@ -0,0 +1,104 @@
+package configstack
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStackRunBufferedOutput(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	var out bytes.Buffer
+
+	// Module a writes its first line before module b, and its second line after it, so that streamed output would be
+	// interleaved
+	aStarted := make(chan struct{})
+	bStarted := make(chan struct{})
+	newModule := func(name string, run func(opts *options.TerragruntOptions) error) *TerraformModule {
+		path := filepath.Join(tmpDir, name)
+		opts, err := options.NewTerragruntOptionsForTest(filepath.Join(path, config.DefaultTerragruntConfigPath))
+		require.NoError(t, err)
+		opts.Writer = &out
+		opts.ErrWriter = &out
+		opts.RunTerragrunt = run
+		return &TerraformModule{Path: path, TerragruntOptions: opts}
+	}
+	a := newModule("a", func(opts *options.TerragruntOptions) error {
+		opts.Writer.Write([]byte("a1\n"))
+		close(aStarted)
+		<-bStarted
+		opts.ErrWriter.Write([]byte("a2\n"))
+		return nil
+	})
+	b := newModule("b", func(opts *options.TerragruntOptions) error {
+		<-aStarted
+		opts.Writer.Write([]byte("b1\n"))
+		close(bStarted)
+		opts.Writer.Write([]byte("b2\n"))
+		return nil
+	})
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(tmpDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.TerraformCommand = "output"
+	opts.TerraformCliArgs = []string{"output"}
+	opts.OutputMode = OutputModeBuffered
+
+	stack := &Stack{Path: tmpDir, Modules: []*TerraformModule{a, b}}
+	require.NoError(t, stack.Run(opts))
+
+	assert.Contains(t, out.String(), "\nOutput of module "+a.Path+":\n\na1\na2\n")
+	assert.Contains(t, out.String(), "\nOutput of module "+b.Path+":\n\nb1\nb2\n")
+}
+
+func TestStackRunUnsupportedOutputMode(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(t.TempDir(), config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.TerraformCommand = "plan"
+	opts.TerraformCliArgs = []string{"plan"}
+	opts.OutputMode = "interleaved"
+
+	stack := &Stack{Path: filepath.Dir(opts.TerragruntConfigPath)}
+	assert.EqualError(t, stack.Run(opts), UnsupportedOutputMode("interleaved").Error())
+}
+
+func TestStackRunBufferedOutputWithPrompts(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		command        string
+		args           []string
+		autoApprove    bool
+		expectedReason string
+	}{
+		{"apply without auto-approve", "apply", []string{"apply"}, false, "apply without -auto-approve"},
+		{"input enabled", "plan", []string{"plan", "-input=true"}, true, "-input=true"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := options.NewTerragruntOptionsForTest(filepath.Join(t.TempDir(), config.DefaultTerragruntConfigPath))
+			require.NoError(t, err)
+			opts.TerraformCommand = testCase.command
+			opts.TerraformCliArgs = testCase.args
+			opts.RunAllAutoApprove = testCase.autoApprove
+			opts.OutputMode = OutputModeBuffered
+
+			stack := &Stack{Path: filepath.Dir(opts.TerragruntConfigPath)}
+			assert.EqualError(t, stack.Run(opts), BufferedOutputWithPrompts(testCase.expectedReason).Error())
+		})
+	}
+}
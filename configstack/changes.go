@@ -0,0 +1,152 @@
+package configstack
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// flagModulesNotAffectedByChanges iterates over a module slice and flags all modules as excluded that are not affected
+// by the files changed since the git ref specified on the TerragruntOptions ChangedSince attribute, and that don't
+// depend, directly or transitively, on a module that is affected.
+func flagModulesNotAffectedByChanges(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) ([]*TerraformModule, error) {
+
+	// If no ChangedSince is specified return the modules list instantly
+	if terragruntOptions.ChangedSince == "" {
+		return modules, nil
+	}
+
+	changedFiles, err := shell.GitChangedFiles(terragruntOptions, terragruntOptions.WorkingDir, terragruntOptions.ChangedSince)
+	if err != nil {
+		return nil, err
+	}
+
+	return flagModulesNotAffectedByFiles(modules, changedFiles, terragruntOptions)
+}
+
+// flagModulesNotAffectedByFiles flags all modules as excluded that are not affected by any of the given changed files,
+// and that don't depend on a module that is affected. A module is affected by a file if the file is:
+//
+//   - in the module folder, and not in the folder of another module nested in it
+//   - a config included by the module
+//   - a file dependency of the module, or in a folder that is a file dependency of the module, such as its local
+//     terraform source
+func flagModulesNotAffectedByFiles(modules []*TerraformModule, changedFiles []string, terragruntOptions *options.TerragruntOptions) ([]*TerraformModule, error) {
+	canonicalChangedFiles := []string{}
+	for _, changedFile := range changedFiles {
+		canonicalPath, err := util.CanonicalPath(changedFile, terragruntOptions.WorkingDir)
+		if err != nil {
+			return nil, err
+		}
+		canonicalChangedFiles = append(canonicalChangedFiles, canonicalPath)
+	}
+
+	affected := map[string]bool{}
+
+	for _, changedFile := range canonicalChangedFiles {
+		if module := findInnermostModuleContainingFile(modules, changedFile); module != nil {
+			terragruntOptions.Logger.Debugf("Module %s is affected by the change to %s", module.Path, changedFile)
+			affected[module.Path] = true
+		}
+	}
+
+	for _, module := range modules {
+		if affected[module.Path] {
+			continue
+		}
+
+		affectingFiles, err := filesAffectingModule(module)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, changedFile := range canonicalChangedFiles {
+			if isAffectedByFile(affectingFiles, changedFile) {
+				terragruntOptions.Logger.Debugf("Module %s is affected by the change to %s", module.Path, changedFile)
+				affected[module.Path] = true
+				break
+			}
+		}
+	}
+
+	// Every module that depends on an affected module is affected as well. Keep propagating until no new module is
+	// found, which handles transitive dependencies regardless of the order of the modules in the slice.
+	for changed := true; changed; {
+		changed = false
+		for _, module := range modules {
+			if affected[module.Path] {
+				continue
+			}
+			for _, dependency := range module.Dependencies {
+				if affected[dependency.Path] {
+					terragruntOptions.Logger.Debugf("Module %s is affected because it depends on %s", module.Path, dependency.Path)
+					affected[module.Path] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	for _, module := range modules {
+		if !affected[module.Path] {
+			module.FlagExcluded = true
+		}
+	}
+
+	return modules, nil
+}
+
+// Returns the module whose folder contains the given file. If the folders of several modules contain the file, because
+// the modules are nested, the innermost module is returned. Returns nil if no module contains the file.
+func findInnermostModuleContainingFile(modules []*TerraformModule, file string) *TerraformModule {
+	var innermostModule *TerraformModule
+	for _, module := range modules {
+		if !isFileInDir(file, module.Path) {
+			continue
+		}
+		if innermostModule == nil || len(module.Path) > len(innermostModule.Path) {
+			innermostModule = module
+		}
+	}
+	return innermostModule
+}
+
+// Returns the canonical paths of the files and folders outside of the module folder that the module depends on: the
+// configs it includes and its file dependencies.
+func filesAffectingModule(module *TerraformModule) ([]string, error) {
+	affectingFiles := []string{}
+
+	for _, includeConfig := range module.Config.ProcessedIncludes {
+		canonicalPath, err := util.CanonicalPath(includeConfig.Path, module.Path)
+		if err != nil {
+			return nil, err
+		}
+		affectingFiles = append(affectingFiles, canonicalPath)
+	}
+
+	return append(affectingFiles, module.FileDependencies...), nil
+}
+
+// Returns true if the given changed file is one of the given affecting files, or is in one of them if it is a folder
+func isAffectedByFile(affectingFiles []string, changedFile string) bool {
+	for _, affectingFile := range affectingFiles {
+		if changedFile == affectingFile || isFileInDir(changedFile, affectingFile) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the given file is located in the given folder or one of its subfolders
+func isFileInDir(file string, dir string) bool {
+	relPath, err := filepath.Rel(dir, file)
+	if err != nil {
+		return false
+	}
+	relPath = filepath.ToSlash(relPath)
+	return relPath != "." && relPath != ".." && !strings.HasPrefix(relPath, "../")
+}
@@ -0,0 +1,109 @@
+package configstack
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagModulesNotAffectedByFiles(t *testing.T) {
+	t.Parallel()
+
+	// Module layout:
+	//   /infra/live/vpc            source = ../../modules/vpc
+	//   /infra/live/app            depends on vpc, includes /infra/live/root.hcl
+	//   /infra/live/app/worker     nested module, depends on app
+	//   /infra/live/db             reads /infra/live/common/env.hcl
+	//   /infra/live/cache          excluded through another flag
+	newModules := func() []*TerraformModule {
+		vpc := &TerraformModule{
+			Path:             "/infra/live/vpc",
+			Config:           config.TerragruntConfig{Terraform: &config.TerraformConfig{Source: ptr("../../modules//vpc")}},
+			FileDependencies: []string{"/infra/modules/vpc"},
+		}
+		app := &TerraformModule{
+			Path:         "/infra/live/app",
+			Dependencies: []*TerraformModule{vpc},
+			Config: config.TerragruntConfig{
+				ProcessedIncludes: map[string]config.IncludeConfig{"": {Path: "../root.hcl"}},
+			},
+		}
+		worker := &TerraformModule{
+			Path:         "/infra/live/app/worker",
+			Dependencies: []*TerraformModule{app},
+		}
+		db := &TerraformModule{
+			Path:             "/infra/live/db",
+			FileDependencies: []string{"/infra/live/common/env.hcl"},
+		}
+		cache := &TerraformModule{
+			Path:         "/infra/live/cache",
+			FlagExcluded: true,
+		}
+		return []*TerraformModule{vpc, app, worker, db, cache}
+	}
+
+	testCases := []struct {
+		name             string
+		changedFiles     []string
+		expectedIncluded []string
+	}{
+		{"no-changes", []string{}, []string{}},
+		{"unrelated-file", []string{"/infra/README.md"}, []string{}},
+		{"module-config", []string{"/infra/live/db/terragrunt.hcl"}, []string{"/infra/live/db"}},
+		{"nested-module-file", []string{"/infra/live/app/worker/main.tf"}, []string{"/infra/live/app/worker"}},
+		{"dependency-propagates-to-dependents", []string{"/infra/live/vpc/terragrunt.hcl"}, []string{"/infra/live/vpc", "/infra/live/app", "/infra/live/app/worker"}},
+		{"local-source", []string{"/infra/modules/vpc/main.tf"}, []string{"/infra/live/vpc", "/infra/live/app", "/infra/live/app/worker"}},
+		{"other-local-module", []string{"/infra/modules/vpc-peering/main.tf"}, []string{}},
+		{"included-config", []string{"/infra/live/root.hcl"}, []string{"/infra/live/app", "/infra/live/app/worker"}},
+		{"read-config", []string{"/infra/live/common/env.hcl"}, []string{"/infra/live/db"}},
+		{"already-excluded", []string{"/infra/live/cache/terragrunt.hcl"}, []string{}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			terragruntOptions, err := options.NewTerragruntOptionsForTest("/infra/live")
+			require.NoError(t, err)
+
+			modules, err := flagModulesNotAffectedByFiles(newModules(), testCase.changedFiles, terragruntOptions)
+			require.NoError(t, err)
+
+			included := []string{}
+			for _, module := range modules {
+				if !module.FlagExcluded {
+					included = append(included, module.Path)
+				}
+			}
+			assert.ElementsMatch(t, testCase.expectedIncluded, included)
+		})
+	}
+}
+
+func TestResolveTerraformModuleRecordsFileDependencies(t *testing.T) {
+	t.Parallel()
+
+	childDir := "../test/fixture-modules/module-m/module-m-child"
+	childConfigPath := filepath.Join(childDir, config.DefaultTerragruntConfigPath)
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("running_module_test")
+	require.NoError(t, err)
+
+	module, err := resolveTerraformModule(canonical(t, childConfigPath), terragruntOptions, nil, mockHowThesePathsWereFound, nil)
+	require.NoError(t, err)
+	require.NotNil(t, module)
+
+	assert.Equal(t, []string{
+		canonical(t, "../test/fixture-modules/module-m/env.hcl"),
+		canonical(t, "../test/fixture-modules/module-m/module-m-child/tier.hcl"),
+		// The placeholder terraform source of the fixture is a local path
+		canonical(t, "../test/fixture-modules/module-m/module-m-child/..."),
+	}, module.FileDependencies)
+	assert.Nil(t, module.TerragruntOptions.FileReadTracker)
+}
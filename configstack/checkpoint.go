@@ -0,0 +1,152 @@
+package configstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The name of the file, relative to the download dir of the stack, where the run-all checkpoint is persisted unless
+// a custom path is set with --terragrunt-checkpoint-file.
+const defaultCheckpointFileName = "run-all-checkpoint.json"
+
+// The commands for which a failed run-all records a checkpoint. Commands that don't change any infrastructure, such as
+// plan or output, are cheap to run again in full.
+var checkpointCommands = []string{"apply", "destroy"}
+
+// RunCheckpoint records which modules of a stack finished successfully during a run-all command that failed, so that
+// the command can be resumed later without running those modules again.
+type RunCheckpoint struct {
+	Command          string   `json:"command"`
+	SucceededModules []string `json:"succeeded_modules"`
+}
+
+// CheckpointFilePath returns the path of the checkpoint file for the stack described by the given options.
+func CheckpointFilePath(terragruntOptions *options.TerragruntOptions) string {
+	if terragruntOptions.CheckpointFile != "" {
+		return terragruntOptions.CheckpointFile
+	}
+	return util.JoinPath(terragruntOptions.DownloadDir, defaultCheckpointFileName)
+}
+
+// ReadRunCheckpoint reads the checkpoint at the given path. Returns nil without an error if the file does not exist.
+func ReadRunCheckpoint(path string) (*RunCheckpoint, error) {
+	if !util.FileExists(path) {
+		return nil, nil
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	var checkpoint RunCheckpoint
+	if err := json.Unmarshal(contents, &checkpoint); err != nil {
+		return nil, errors.WithStackTrace(InvalidCheckpointFile{Path: path, Err: err})
+	}
+
+	return &checkpoint, nil
+}
+
+// Write persists the checkpoint to the given path, creating the parent directories if necessary.
+func (checkpoint *RunCheckpoint) Write(path string) error {
+	contents, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return errors.WithStackTrace(os.WriteFile(path, contents, 0644))
+}
+
+// ResumeFromCheckpoint reads the checkpoint of a previous run-all invocation and marks the modules that finished
+// successfully in that run as already applied, so that only the failed modules and their dependents are run again.
+// Returns the number of modules that were marked.
+func (stack *Stack) ResumeFromCheckpoint(terragruntOptions *options.TerragruntOptions) (int, error) {
+	checkpointPath := CheckpointFilePath(terragruntOptions)
+
+	checkpoint, err := ReadRunCheckpoint(checkpointPath)
+	if err != nil {
+		return 0, err
+	}
+	if checkpoint == nil {
+		terragruntOptions.Logger.Infof("No checkpoint found at %s. Running all modules.", checkpointPath)
+		return 0, nil
+	}
+
+	if checkpoint.Command != terragruntOptions.TerraformCommand {
+		return 0, errors.WithStackTrace(CheckpointCommandMismatch{Path: checkpointPath, CheckpointCommand: checkpoint.Command, Command: terragruntOptions.TerraformCommand})
+	}
+
+	resumed := 0
+	for _, module := range stack.Modules {
+		if util.ListContainsElement(checkpoint.SucceededModules, module.Path) {
+			terragruntOptions.Logger.Debugf("Module %s finished successfully in the previous run and will be skipped", module.Path)
+			module.AssumeAlreadyApplied = true
+			resumed++
+		}
+	}
+
+	terragruntOptions.Logger.Infof("Resuming from checkpoint %s: skipping %d module(s) that already finished successfully", checkpointPath, resumed)
+	return resumed, nil
+}
+
+// saveCheckpoint records the modules that finished successfully in this run, together with the modules that were
+// skipped because they had already succeeded in a resumed run. If every module finished successfully, the checkpoint
+// is no longer needed and is removed instead. Only apply and destroy record checkpoints.
+func (stack *Stack) saveCheckpoint(terragruntOptions *options.TerragruntOptions, runningModules map[string]*runningModule) error {
+	if !util.ListContainsElement(checkpointCommands, terragruntOptions.TerraformCommand) {
+		return nil
+	}
+
+	checkpointPath := CheckpointFilePath(terragruntOptions)
+
+	if collectErrors(runningModules) == nil {
+		if util.FileExists(checkpointPath) {
+			terragruntOptions.Logger.Debugf("All modules finished successfully. Removing checkpoint %s", checkpointPath)
+			return errors.WithStackTrace(os.Remove(checkpointPath))
+		}
+		return nil
+	}
+
+	checkpoint := &RunCheckpoint{Command: terragruntOptions.TerraformCommand, SucceededModules: []string{}}
+	for path, module := range runningModules {
+		if module.Status == Finished && module.Err == nil && !module.RunElsewhere {
+			checkpoint.SucceededModules = append(checkpoint.SucceededModules, path)
+		}
+	}
+	sort.Strings(checkpoint.SucceededModules)
+
+	terragruntOptions.Logger.Infof("Saving checkpoint with %d successful module(s) to %s. Rerun with --terragrunt-resume to continue from here.", len(checkpoint.SucceededModules), checkpointPath)
+	return checkpoint.Write(checkpointPath)
+}
+
+// Custom error types
+
+type InvalidCheckpointFile struct {
+	Path string
+	Err  error
+}
+
+func (err InvalidCheckpointFile) Error() string {
+	return fmt.Sprintf("Could not parse run-all checkpoint file %s: %v", err.Path, err.Err)
+}
+
+type CheckpointCommandMismatch struct {
+	Path              string
+	CheckpointCommand string
+	Command           string
+}
+
+func (err CheckpointCommandMismatch) Error() string {
+	return fmt.Sprintf("Can't resume %s from the checkpoint %s, which was recorded for %s. Rerun %s with --terragrunt-resume, or remove the checkpoint to run all modules.", err.Command, err.Path, err.CheckpointCommand, err.CheckpointCommand)
+}
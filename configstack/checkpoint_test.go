@@ -0,0 +1,132 @@
+package configstack
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveCheckpointAndResume(t *testing.T) {
+	t.Parallel()
+
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	stackOptions, err := options.NewTerragruntOptionsForTest("stack")
+	require.NoError(t, err)
+	stackOptions.TerraformCommand = "apply"
+	stackOptions.CheckpointFile = checkpointPath
+
+	expectedErrB := errors.New("Expected error for module b")
+
+	aRan := false
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "a", nil, &aRan),
+	}
+
+	bRan := false
+	moduleB := &TerraformModule{
+		Path:              "b",
+		Dependencies:      []*TerraformModule{moduleA},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "b", expectedErrB, &bRan),
+	}
+
+	cRan := false
+	moduleC := &TerraformModule{
+		Path:              "c",
+		Dependencies:      []*TerraformModule{moduleB},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "c", nil, &cRan),
+	}
+
+	stack := &Stack{Path: "stack", Modules: []*TerraformModule{moduleA, moduleB, moduleC}}
+
+	runningModules, err := toRunningModules(stack.Modules, NormalOrder)
+	require.NoError(t, err)
+	assert.Error(t, runModules(context.Background(), runningModules, options.DefaultParallelism))
+
+	require.NoError(t, stack.saveCheckpoint(stackOptions, runningModules))
+
+	checkpoint, err := ReadRunCheckpoint(checkpointPath)
+	require.NoError(t, err)
+	assert.Equal(t, &RunCheckpoint{Command: "apply", SucceededModules: []string{"a"}}, checkpoint)
+
+	resumed, err := stack.ResumeFromCheckpoint(stackOptions)
+	require.NoError(t, err)
+	assert.Equal(t, 1, resumed)
+	assert.True(t, moduleA.AssumeAlreadyApplied)
+	assert.False(t, moduleB.AssumeAlreadyApplied)
+	assert.False(t, moduleC.AssumeAlreadyApplied)
+
+	// A successful run removes the checkpoint.
+	aRan, bRan, cRan = false, false, false
+	moduleB.TerragruntOptions = optionsWithMockTerragruntCommand(t, "b", nil, &bRan)
+
+	runningModules, err = toRunningModules(stack.Modules, NormalOrder)
+	require.NoError(t, err)
+	assert.NoError(t, runModules(context.Background(), runningModules, options.DefaultParallelism))
+	assert.False(t, aRan)
+	assert.True(t, bRan)
+	assert.True(t, cRan)
+
+	require.NoError(t, stack.saveCheckpoint(stackOptions, runningModules))
+	assert.False(t, util.FileExists(checkpointPath))
+}
+
+func TestResumeFromCheckpointDifferentCommand(t *testing.T) {
+	t.Parallel()
+
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+	require.NoError(t, (&RunCheckpoint{Command: "destroy", SucceededModules: []string{"a"}}).Write(checkpointPath))
+
+	stackOptions, err := options.NewTerragruntOptionsForTest("stack")
+	require.NoError(t, err)
+	stackOptions.TerraformCommand = "apply"
+	stackOptions.CheckpointFile = checkpointPath
+
+	moduleA := &TerraformModule{Path: "a", TerragruntOptions: stackOptions}
+	stack := &Stack{Path: "stack", Modules: []*TerraformModule{moduleA}}
+
+	_, err = stack.ResumeFromCheckpoint(stackOptions)
+	var mismatch CheckpointCommandMismatch
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, "destroy", mismatch.CheckpointCommand)
+	assert.False(t, moduleA.AssumeAlreadyApplied)
+}
+
+func TestSaveCheckpointOnlyForApplyAndDestroy(t *testing.T) {
+	t.Parallel()
+
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+	stackOptions, err := options.NewTerragruntOptionsForTest("stack")
+	require.NoError(t, err)
+	stackOptions.TerraformCommand = "plan"
+	stackOptions.CheckpointFile = checkpointPath
+
+	moduleA := &TerraformModule{Path: "a", TerragruntOptions: stackOptions}
+	stack := &Stack{Path: "stack", Modules: []*TerraformModule{moduleA}}
+	runningModules := map[string]*runningModule{
+		"a": {Module: moduleA, Status: Finished, Err: errors.New("plan failed")},
+	}
+
+	require.NoError(t, stack.saveCheckpoint(stackOptions, runningModules))
+	assert.False(t, util.FileExists(checkpointPath))
+}
+
+func TestReadRunCheckpointMissingFile(t *testing.T) {
+	t.Parallel()
+
+	checkpoint, err := ReadRunCheckpoint(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	assert.NoError(t, err)
+	assert.Nil(t, checkpoint)
+}
@@ -0,0 +1,173 @@
+package configstack
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// confirmEachPrompt asks the user a yes/no question. It is shell.PromptUserForYesNo, except in tests.
+type confirmEachPrompt func(prompt string, terragruntOptions *options.TerragruntOptions) (bool, error)
+
+// prepareConfirmEach makes every module of the stack, instead of running apply or destroy directly, save a plan to a new
+// temporary folder, show it and apply it only if the user approves it through the given prompt. A module whose plan is
+// rejected finishes with a ModuleNotApproved error, so the modules that depend on it are skipped. Returns the path of
+// the folder, which the caller must remove once the run has finished.
+func (stack *Stack) prepareConfirmEach(prompt confirmEachPrompt) (string, error) {
+	dir, err := os.MkdirTemp("", "terragrunt-confirm-each")
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+
+	// Modules of the same level plan concurrently, but their plans are shown and confirmed one module at a time. While
+	// a prompt is shown, the output of the modules that are planned or applied meanwhile is held back, so that it doesn't
+	// mix with the plan and the prompt.
+	var promptMutex sync.Mutex
+
+	for n, module := range stack.Modules {
+		planFile := filepath.Join(dir, fmt.Sprintf("module-%d.tfplan", n))
+		planJSONFile := filepath.Join(dir, fmt.Sprintf("module-%d.json", n))
+		runTerragrunt := module.TerragruntOptions.RunTerragrunt
+		modulePath := module.Path
+
+		module.TerragruntOptions.RunTerragrunt = func(opts *options.TerragruntOptions) error {
+			planOpts := opts.Clone(opts.TerragruntConfigPath)
+			planOpts.TerraformCommand = "plan"
+			planOpts.TerraformCliArgs = confirmEachPlanArgs(opts.TerraformCliArgs, planFile)
+			planOpts.PlanJSONFile = planJSONFile
+			var planOutput bytes.Buffer
+			planOpts.Writer = &planOutput
+			planOpts.ErrWriter = &lockedWriter{mutex: &promptMutex, writer: opts.ErrWriter}
+
+			if err := runTerragrunt(planOpts); err != nil {
+				return err
+			}
+
+			if data, err := os.ReadFile(planJSONFile); err == nil {
+				if summary, err := parsePlanSummary(data); err == nil && !summary.HasChanges() {
+					opts.Logger.Infof("The plan of module %s has no changes, so there is nothing to apply", modulePath)
+					return nil
+				}
+			}
+
+			promptMutex.Lock()
+			approved, err := confirmPlan(opts, modulePath, planOutput.Bytes(), prompt)
+			promptMutex.Unlock()
+			if err != nil {
+				return err
+			}
+			if !approved {
+				return errors.WithStackTrace(ModuleNotApproved{Path: modulePath})
+			}
+
+			applyOpts := opts.Clone(opts.TerragruntConfigPath)
+			applyOpts.TerraformCommand = "apply"
+			applyOpts.TerraformCliArgs = confirmEachApplyArgs(opts.TerraformCliArgs, planFile)
+			applyOpts.Writer = &lockedWriter{mutex: &promptMutex, writer: opts.Writer}
+			applyOpts.ErrWriter = &lockedWriter{mutex: &promptMutex, writer: opts.ErrWriter}
+			return runTerragrunt(applyOpts)
+		}
+	}
+	return dir, nil
+}
+
+// Returns the arguments of the plan that is shown for approval in place of the given apply or destroy arguments.
+func confirmEachPlanArgs(args []string, planFile string) []string {
+	planArgs := []string{"plan"}
+	if util.FirstArg(args) == "destroy" {
+		planArgs = append(planArgs, "-destroy")
+	}
+	if len(args) > 1 {
+		for _, arg := range args[1:] {
+			if arg != "-auto-approve" && arg != "-auto-approve=true" {
+				planArgs = append(planArgs, arg)
+			}
+		}
+	}
+	return append(planArgs, "-out="+planFile)
+}
+
+// The options of apply that can be used along with a saved plan, and so are passed on from the apply or destroy
+// arguments of the user to the apply of the approved plan, mapped to whether they take a value. The others, such as
+// -var or -target, are already applied by the plan.
+var planFileApplyOptions = map[string]bool{
+	"-backup":           true,
+	"-compact-warnings": false,
+	"-json":             false,
+	"-lock":             false,
+	"-lock-timeout":     true,
+	"-no-color":         false,
+	"-parallelism":      true,
+	"-state":            true,
+	"-state-out":        true,
+}
+
+// Returns the arguments of the apply of the given saved plan, in place of the given apply or destroy arguments.
+func confirmEachApplyArgs(args []string, planFile string) []string {
+	applyArgs := []string{"apply", "-input=false"}
+	for i := 1; i < len(args); i++ {
+		nameAndValue := strings.SplitN(args[i], "=", 2)
+		takesValue, isPlanFileOption := planFileApplyOptions[nameAndValue[0]]
+		if !isPlanFileOption {
+			continue
+		}
+		applyArgs = append(applyArgs, args[i])
+		// The value can also be passed as the next argument, as in -parallelism 2
+		if takesValue && len(nameAndValue) == 1 && i+1 < len(args) {
+			i++
+			applyArgs = append(applyArgs, args[i])
+		}
+	}
+	return append(applyArgs, planFile)
+}
+
+// Shows the given plan output of a module and asks the user whether to apply it.
+func confirmPlan(terragruntOptions *options.TerragruntOptions, modulePath string, planOutput []byte, prompt confirmEachPrompt) (bool, error) {
+	header := fmt.Sprintf("\nPlan of module %s:\n\n", modulePath)
+	if _, err := terragruntOptions.Writer.Write([]byte(header)); err != nil {
+		return false, errors.WithStackTrace(err)
+	}
+	if _, err := terragruntOptions.Writer.Write(planOutput); err != nil {
+		return false, errors.WithStackTrace(err)
+	}
+
+	approved, err := prompt(fmt.Sprintf("Apply the plan of module %s?", modulePath), terragruntOptions)
+	if err != nil {
+		return false, err
+	}
+	if !approved {
+		terragruntOptions.Logger.Infof("The plan of module %s was rejected. Modules that depend on it will be skipped.", modulePath)
+	}
+	return approved, nil
+}
+
+// lockedWriter writes to the wrapped writer while holding the given mutex, so that nothing is written while another
+// goroutine holds it.
+type lockedWriter struct {
+	mutex  *sync.Mutex
+	writer io.Writer
+}
+
+func (writer *lockedWriter) Write(p []byte) (int, error) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	return writer.writer.Write(p)
+}
+
+// Custom error types
+
+type ModuleNotApproved struct {
+	Path string
+}
+
+func (err ModuleNotApproved) Error() string {
+	return fmt.Sprintf("The plan of module %s was not approved, so it was not applied", err.Path)
+}
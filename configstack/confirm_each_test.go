@@ -0,0 +1,128 @@
+package configstack
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmEachPlanArgs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"apply", "-auto-approve", "-input=false"}, []string{"plan", "-input=false", "-out=a.tfplan"}},
+		{[]string{"destroy", "-input=false", "-var", "x=1"}, []string{"plan", "-destroy", "-input=false", "-var", "x=1", "-out=a.tfplan"}},
+		{[]string{"apply"}, []string{"plan", "-out=a.tfplan"}},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, confirmEachPlanArgs(testCase.args, "a.tfplan"))
+	}
+}
+
+func TestConfirmEachApplyArgs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"apply", "-input=false"}, []string{"apply", "-input=false", "a.tfplan"}},
+		{
+			[]string{"apply", "-input=false", "-parallelism=2", "-lock-timeout=5m", "-no-color", "-var", "x=1", "-target=module.a"},
+			[]string{"apply", "-input=false", "-parallelism=2", "-lock-timeout=5m", "-no-color", "a.tfplan"},
+		},
+		{[]string{"destroy", "-auto-approve", "-lock=false"}, []string{"apply", "-input=false", "-lock=false", "a.tfplan"}},
+		{[]string{"apply", "-parallelism", "2", "-var-file", "prod.tfvars"}, []string{"apply", "-input=false", "-parallelism", "2", "a.tfplan"}},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, confirmEachApplyArgs(testCase.args, "a.tfplan"))
+	}
+}
+
+func TestStackConfirmEach(t *testing.T) {
+	t.Parallel()
+
+	var mutex sync.Mutex
+	runArgs := map[string][][]string{}
+	var out bytes.Buffer
+
+	newModule := func(path string, planJSON string, dependencies ...*TerraformModule) *TerraformModule {
+		opts, err := options.NewTerragruntOptionsForTest(path)
+		require.NoError(t, err)
+		opts.TerraformCommand = "apply"
+		opts.TerraformCliArgs = []string{"apply", "-input=false"}
+		opts.Writer = &out
+		opts.RunTerragrunt = func(opts *options.TerragruntOptions) error {
+			mutex.Lock()
+			runArgs[path] = append(runArgs[path], opts.TerraformCliArgs)
+			mutex.Unlock()
+			if opts.TerraformCommand == "plan" {
+				if _, err := opts.Writer.Write([]byte("plan of " + path + "\n")); err != nil {
+					return err
+				}
+				return os.WriteFile(opts.PlanJSONFile, []byte(planJSON), 0644)
+			}
+			return nil
+		}
+		return &TerraformModule{Path: path, Dependencies: dependencies, Config: config.TerragruntConfig{}, TerragruntOptions: opts}
+	}
+
+	changes := `{"resource_changes": [{"change": {"actions": ["create"]}}]}`
+	moduleA := newModule("a", changes)
+	moduleB := newModule("b", changes, moduleA)
+	moduleC := newModule("c", changes, moduleB)
+	moduleD := newModule("d", `{"resource_changes": []}`)
+
+	prompted := []string{}
+	prompt := func(prompt string, _ *options.TerragruntOptions) (bool, error) {
+		prompted = append(prompted, prompt)
+		return !strings.Contains(prompt, "module b"), nil
+	}
+
+	stack := &Stack{Path: "/stack", Modules: []*TerraformModule{moduleA, moduleB, moduleC, moduleD}}
+	dir, err := stack.prepareConfirmEach(prompt)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	runningModules, err := toRunningModules(stack.Modules, NormalOrder)
+	require.NoError(t, err)
+	startTime := time.Now()
+	assert.Error(t, runModules(context.Background(), runningModules, options.DefaultParallelism))
+
+	assert.ElementsMatch(t, []string{"Apply the plan of module a?", "Apply the plan of module b?"}, prompted)
+	assert.Contains(t, out.String(), "Plan of module a:\n\nplan of a\n")
+
+	require.Len(t, runArgs["a"], 2)
+	assert.Equal(t, "plan", runArgs["a"][0][0])
+	planFile := strings.TrimPrefix(runArgs["a"][0][len(runArgs["a"][0])-1], "-out=")
+	assert.Equal(t, []string{"apply", "-input=false", planFile}, runArgs["a"][1])
+	assert.Len(t, runArgs["b"], 1)
+	assert.Empty(t, runArgs["c"])
+	assert.Len(t, runArgs["d"], 1)
+
+	report := newRunReport("apply", startTime, time.Now(), stack.Modules, runningModules)
+	statuses := map[string]ModuleRunStatus{}
+	for _, module := range report.Modules {
+		statuses[module.Path] = module.Status
+	}
+	assert.Equal(t, map[string]ModuleRunStatus{
+		"a": ModuleSucceeded,
+		"b": ModuleNotApprovedStatus,
+		"c": ModuleSkippedDependencyFailed,
+		"d": ModuleSucceeded,
+	}, statuses)
+}
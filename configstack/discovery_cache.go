@@ -0,0 +1,242 @@
+package configstack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The version of the format of the discovery cache file. Files written in any other version are ignored.
+const discoveryCacheVersion = 4
+
+// discoveryCache persists, between runs, the parts of each module's config that are parsed to find the stack, so that
+// modules whose config has not changed don't have to be parsed again: the terraform source, the dependency paths, the
+// includes, the concurrency group and limits, and whether the config has an exclude block. Each entry is only reused if
+// the content of the config and of every file it includes or reads is the same as when the entry was stored, so the
+// cached values of expressions that depend on anything else, such as get_env, run_cmd or get_terraform_command, are
+// frozen until one of those files changes. The exclude block itself is not cached for that reason, and is evaluated
+// again on every run.
+type discoveryCache struct {
+	Version int                             `json:"version"`
+	Entries map[string]*discoveryCacheEntry `json:"entries"`
+
+	path  string
+	dirty bool
+
+	// The hashes of the files read since the cache was opened or last saved, keyed by path, so that a root config
+	// included by every module is only hashed once per stack
+	fileHashes map[string]string
+
+	mutex sync.Mutex
+}
+
+// discoveryCacheEntry is the part of the config of a single module, keyed by config path in the cache, that is parsed
+// to find the stack.
+type discoveryCacheEntry struct {
+	// The SHA256 of the content of the config and of every file it includes or reads, keyed by path
+	FileHashes map[string]string `json:"file_hashes"`
+
+	TerraformSource   *string               `json:"terraform_source,omitempty"`
+	DependencyPaths   []string              `json:"dependency_paths,omitempty"`
+	Includes          config.IncludeConfigs `json:"includes,omitempty"`
+	ConcurrencyGroup  string                `json:"concurrency_group,omitempty"`
+	ConcurrencyLimits map[string]int        `json:"concurrency_limits,omitempty"`
+	ReadFilePaths     []string              `json:"read_file_paths,omitempty"`
+
+	// Whether the config has an exclude block. The block itself is not cached, as its condition can depend on the
+	// command being run, the env vars or the CLI args, so it is evaluated again on every run.
+	HasExclude bool `json:"has_exclude,omitempty"`
+}
+
+// The discovery caches opened by this process, keyed by path, which are shared by all the stacks found in the run.
+var (
+	openDiscoveryCaches      = map[string]*discoveryCache{}
+	openDiscoveryCachesMutex sync.Mutex
+)
+
+// openDiscoveryCache returns the discovery cache in the file set with --terragrunt-discovery-cache-file, or nil if
+// no file is set. A missing or unreadable file gives an empty cache, which is written when modules are added to it.
+func openDiscoveryCache(terragruntOptions *options.TerragruntOptions) *discoveryCache {
+	if terragruntOptions.DiscoveryCacheFile == "" {
+		return nil
+	}
+
+	path, err := filepath.Abs(terragruntOptions.DiscoveryCacheFile)
+	if err != nil {
+		path = terragruntOptions.DiscoveryCacheFile
+	}
+
+	openDiscoveryCachesMutex.Lock()
+	defer openDiscoveryCachesMutex.Unlock()
+
+	if cache, isOpen := openDiscoveryCaches[path]; isOpen {
+		return cache
+	}
+
+	cache := &discoveryCache{}
+	if contents, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(contents, cache); err != nil {
+			terragruntOptions.Logger.Warnf("Ignoring the discovery cache %s, which can't be parsed: %v", path, err)
+			cache = &discoveryCache{}
+		}
+	}
+	if cache.Version != discoveryCacheVersion || cache.Entries == nil {
+		cache.Version = discoveryCacheVersion
+		cache.Entries = map[string]*discoveryCacheEntry{}
+	}
+	cache.path = path
+	cache.fileHashes = map[string]string{}
+
+	openDiscoveryCaches[path] = cache
+	return cache
+}
+
+// Get returns the partially parsed config and the paths of the files read while parsing the given config, if the cache
+// has an entry for the config and none of the files the entry was built from has changed since, along with whether the
+// config has an exclude block, which is not part of the returned config and must be parsed again. Returns nil otherwise.
+func (cache *discoveryCache) Get(terragruntConfigPath string) (*config.TerragruntConfig, []string, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, hasEntry := cache.Entries[terragruntConfigPath]
+	if !hasEntry || len(entry.FileHashes) == 0 {
+		return nil, nil, false
+	}
+
+	for path, expectedHash := range entry.FileHashes {
+		if cache.hashFile(path) != expectedHash {
+			return nil, nil, false
+		}
+	}
+
+	terragruntConfig := &config.TerragruntConfig{
+		IsPartial:         true,
+		ProcessedIncludes: entry.Includes,
+		ConcurrencyGroup:  entry.ConcurrencyGroup,
+		ConcurrencyLimits: entry.ConcurrencyLimits,
+	}
+	if entry.TerraformSource != nil {
+		terragruntConfig.Terraform = &config.TerraformConfig{Source: entry.TerraformSource}
+	}
+	if entry.DependencyPaths != nil {
+		terragruntConfig.Dependencies = &config.ModuleDependencies{Paths: entry.DependencyPaths}
+	}
+	return terragruntConfig, entry.ReadFilePaths, entry.HasExclude
+}
+
+// Put stores the parts of the given partially parsed config that are needed to find the stack, along with the hashes
+// of the config and of the files it includes and reads.
+func (cache *discoveryCache) Put(terragruntConfigPath string, modulePath string, terragruntConfig *config.TerragruntConfig, readFilePaths []string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry := &discoveryCacheEntry{
+		FileHashes:        map[string]string{},
+		Includes:          terragruntConfig.ProcessedIncludes,
+		ConcurrencyGroup:  terragruntConfig.ConcurrencyGroup,
+		ConcurrencyLimits: terragruntConfig.ConcurrencyLimits,
+		ReadFilePaths:     readFilePaths,
+		HasExclude:        terragruntConfig.Exclude != nil,
+	}
+	if terragruntConfig.Terraform != nil {
+		entry.TerraformSource = terragruntConfig.Terraform.Source
+	}
+
+	// Store the dependencies as canonical paths, so that they don't have to be resolved again when crosslinking
+	if terragruntConfig.Dependencies != nil {
+		entry.DependencyPaths = []string{}
+		for _, dependencyPath := range terragruntConfig.Dependencies.Paths {
+			canonicalPath, err := util.CanonicalPath(dependencyPath, modulePath)
+			if err != nil {
+				return
+			}
+			entry.DependencyPaths = append(entry.DependencyPaths, canonicalPath)
+		}
+	}
+
+	files := []string{terragruntConfigPath}
+	for _, includeConfig := range terragruntConfig.ProcessedIncludes {
+		files = append(files, includeConfig.Path)
+	}
+	files = append(files, readFilePaths...)
+	for _, file := range files {
+		canonicalPath, err := util.CanonicalPath(file, modulePath)
+		if err != nil {
+			return
+		}
+		hash := cache.hashFile(canonicalPath)
+		if hash == "" {
+			// A file that can't be read can't be checked for changes, so the config is not cached
+			return
+		}
+		entry.FileHashes[canonicalPath] = hash
+	}
+
+	cache.Entries[terragruntConfigPath] = entry
+	cache.dirty = true
+}
+
+// Save writes the cache to its file, if modules were added to it since it was opened or last saved. Files are hashed
+// again after a save, in case they are changed before the next stack is found.
+func (cache *discoveryCache) Save() error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.fileHashes = map[string]string{}
+	if !cache.dirty {
+		return nil
+	}
+
+	contents, err := json.Marshal(cache)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cache.path), os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	// Write to a temporary file first and rename it, so that concurrent runs never read a partially written cache
+	tmpFile, err := os.CreateTemp(filepath.Dir(cache.path), ".discovery-cache-")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(contents); err != nil {
+		tmpFile.Close()
+		return errors.WithStackTrace(err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.Rename(tmpFile.Name(), cache.path); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	cache.dirty = false
+	return nil
+}
+
+// Returns the SHA256 of the content of the file at the given path, or an empty string if the file can't be read.
+func (cache *discoveryCache) hashFile(path string) string {
+	if hash, isHashed := cache.fileHashes[path]; isHashed {
+		return hash
+	}
+
+	hash := ""
+	if contents, err := os.ReadFile(path); err == nil {
+		sum := sha256.Sum256(contents)
+		hash = hex.EncodeToString(sum[:])
+	}
+	cache.fileHashes[path] = hash
+	return hash
+}
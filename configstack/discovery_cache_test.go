@@ -0,0 +1,140 @@
+package configstack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTerraformModulesWithDiscoveryCache(t *testing.T) {
+	t.Parallel()
+
+	stackDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+
+	writeFile := func(path string, contents string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(stackDir, path)), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(stackDir, path), []byte(contents), 0644))
+	}
+	writeFile("root.hcl", `locals { group = "networking" }`)
+	writeFile("vpc/main.tf", "")
+	writeFile("vpc/terragrunt.hcl", `
+include "root" {
+  path = find_in_parent_folders("root.hcl")
+}
+concurrency_group = "networking"
+`)
+	writeFile("app/main.tf", "")
+	writeFile("app/terragrunt.hcl", `
+dependencies {
+  paths = ["../vpc"]
+}
+`)
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(stackDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	terragruntOptions.DiscoveryCacheFile = filepath.Join(stackDir, ".terragrunt-cache", "discovery.json")
+
+	vpcConfigPath := filepath.Join(stackDir, "vpc", config.DefaultTerragruntConfigPath)
+	appConfigPath := filepath.Join(stackDir, "app", config.DefaultTerragruntConfigPath)
+	configPaths := []string{vpcConfigPath, appConfigPath}
+
+	resolve := func() map[string]*TerraformModule {
+		modules, err := ResolveTerraformModules(configPaths, terragruntOptions, nil, mockHowThesePathsWereFound)
+		require.NoError(t, err)
+		moduleMap := map[string]*TerraformModule{}
+		for _, module := range modules {
+			moduleMap[filepath.Base(module.Path)] = module
+		}
+		return moduleMap
+	}
+
+	modules := resolve()
+	assert.Equal(t, "networking", modules["vpc"].Config.ConcurrencyGroup)
+	require.Len(t, modules["app"].Dependencies, 1)
+	assert.Equal(t, modules["vpc"], modules["app"].Dependencies[0])
+	require.FileExists(t, terragruntOptions.DiscoveryCacheFile)
+
+	cache := openDiscoveryCache(terragruntOptions)
+	require.Contains(t, cache.Entries, vpcConfigPath)
+	require.Contains(t, cache.Entries, appConfigPath)
+	assert.Contains(t, cache.Entries[vpcConfigPath].FileHashes, filepath.Join(stackDir, "root.hcl"))
+	assert.Equal(t, []string{filepath.Join(stackDir, "vpc")}, cache.Entries[appConfigPath].DependencyPaths)
+
+	// Unchanged configs are not parsed again, so changes to the cached entry show up in the modules
+	cache.Entries[vpcConfigPath].ConcurrencyGroup = "from-cache"
+	modules = resolve()
+	assert.Equal(t, "from-cache", modules["vpc"].Config.ConcurrencyGroup)
+	require.Len(t, modules["app"].Dependencies, 1)
+	assert.Equal(t, modules["vpc"], modules["app"].Dependencies[0])
+
+	// Changing an included config invalidates the entries of the configs that include it
+	writeFile("root.hcl", `locals { group = "network" }`)
+	modules = resolve()
+	assert.Equal(t, "networking", modules["vpc"].Config.ConcurrencyGroup)
+}
+
+func TestDiscoveryCacheIgnoresInvalidFile(t *testing.T) {
+	t.Parallel()
+
+	cacheFile := filepath.Join(t.TempDir(), "discovery.json")
+	require.NoError(t, os.WriteFile(cacheFile, []byte("not json"), 0644))
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Dir(cacheFile))
+	require.NoError(t, err)
+	terragruntOptions.DiscoveryCacheFile = cacheFile
+
+	cache := openDiscoveryCache(terragruntOptions)
+	require.NotNil(t, cache)
+	assert.Empty(t, cache.Entries)
+
+	terragruntOptions.DiscoveryCacheFile = ""
+	assert.Nil(t, openDiscoveryCache(terragruntOptions))
+}
+
+func TestResolveTerraformModulesWithDiscoveryCacheEvaluatesExclude(t *testing.T) {
+	t.Parallel()
+
+	stackDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+
+	configPath := filepath.Join(stackDir, "app", config.DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(configPath), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(stackDir, "app", "main.tf"), []byte(""), 0644))
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+exclude {
+  if = get_terraform_command() == "destroy"
+}
+`), 0644))
+
+	cacheFile := filepath.Join(stackDir, ".terragrunt-cache", "discovery.json")
+	resolve := func(command string) *TerraformModule {
+		terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(stackDir, config.DefaultTerragruntConfigPath))
+		require.NoError(t, err)
+		terragruntOptions.DiscoveryCacheFile = cacheFile
+		terragruntOptions.TerraformCommand = command
+
+		modules, err := ResolveTerraformModules([]string{configPath}, terragruntOptions, nil, mockHowThesePathsWereFound)
+		require.NoError(t, err)
+		require.Len(t, modules, 1)
+		return modules[0]
+	}
+
+	assert.False(t, resolve("plan").FlagExcluded)
+
+	// The second run uses the cached config, but the exclude block is evaluated again for the new command
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(stackDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	terragruntOptions.DiscoveryCacheFile = cacheFile
+	cache := openDiscoveryCache(terragruntOptions)
+	require.Contains(t, cache.Entries, configPath)
+	assert.True(t, cache.Entries[configPath].HasExclude)
+
+	assert.True(t, resolve("destroy").FlagExcluded)
+	assert.False(t, resolve("plan").FlagExcluded)
+}
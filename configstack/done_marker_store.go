@@ -0,0 +1,170 @@
+package configstack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/gruntwork-io/terragrunt/aws_helper"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+)
+
+// DoneMarker records that a module run by one shard of a distributed run-all has finished, so that the other shards
+// can run the modules that depend on it.
+type DoneMarker struct {
+	Module    string `json:"module"`
+	Shard     int    `json:"shard"`
+	Succeeded bool   `json:"succeeded"`
+	Error     string `json:"error,omitempty"`
+}
+
+// DoneMarkerStore persists the done markers that the shards of a distributed run-all use to coordinate. All the
+// shards of a run must use the same store, and a store must not be reused between runs.
+type DoneMarkerStore interface {
+	// Write stores the given marker under the given key, replacing any marker already stored there.
+	Write(key string, marker *DoneMarker) error
+
+	// Read returns the marker stored under the given key, or nil if there is none yet.
+	Read(key string) (*DoneMarker, error)
+}
+
+// NewDoneMarkerStore returns the store at the given location, which is either an S3 URL, such as
+// s3://bucket/prefix?region=us-east-1, or the path of a local folder, which can be on a file system shared by the shards.
+func NewDoneMarkerStore(location string, terragruntOptions *options.TerragruntOptions) (DoneMarkerStore, error) {
+	if !strings.HasPrefix(location, "s3://") {
+		return &localDoneMarkerStore{Dir: location}, nil
+	}
+
+	storeURL, err := url.Parse(location)
+	if err != nil || storeURL.Host == "" {
+		return nil, errors.WithStackTrace(InvalidDoneMarkerStore(location))
+	}
+
+	var sessionConfig *aws_helper.AwsSessionConfig
+	if region := storeURL.Query().Get("region"); region != "" {
+		sessionConfig = &aws_helper.AwsSessionConfig{Region: region}
+	}
+
+	session, err := aws_helper.CreateAwsSession(sessionConfig, terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3DoneMarkerStore{
+		Bucket: storeURL.Host,
+		Prefix: strings.Trim(storeURL.Path, "/"),
+		Client: s3.New(session),
+	}, nil
+}
+
+// localDoneMarkerStore keeps each marker as a JSON file in a local folder.
+type localDoneMarkerStore struct {
+	Dir string
+}
+
+func (store *localDoneMarkerStore) Write(key string, marker *DoneMarker) error {
+	contents, err := json.Marshal(marker)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	markerPath := filepath.Join(store.Dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(markerPath), os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	// Write to a temporary file first and rename it, so that readers never see a partially written marker
+	tmpFile, err := os.CreateTemp(filepath.Dir(markerPath), ".done-marker-")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(contents); err != nil {
+		tmpFile.Close()
+		return errors.WithStackTrace(err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return errors.WithStackTrace(os.Rename(tmpFile.Name(), markerPath))
+}
+
+func (store *localDoneMarkerStore) Read(key string) (*DoneMarker, error) {
+	contents, err := os.ReadFile(filepath.Join(store.Dir, filepath.FromSlash(key)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return parseDoneMarker(contents)
+}
+
+// s3DoneMarkerStore keeps each marker as a JSON object in an S3 bucket.
+type s3DoneMarkerStore struct {
+	Bucket string
+	Prefix string
+	Client *s3.S3
+}
+
+func (store *s3DoneMarkerStore) Write(key string, marker *DoneMarker) error {
+	contents, err := json.Marshal(marker)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	_, err = store.Client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(store.Bucket),
+		Key:    aws.String(path.Join(store.Prefix, key)),
+		Body:   bytes.NewReader(contents),
+	})
+	return errors.WithStackTrace(err)
+}
+
+func (store *s3DoneMarkerStore) Read(key string) (*DoneMarker, error) {
+	output, err := store.Client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(store.Bucket),
+		Key:    aws.String(path.Join(store.Prefix, key)),
+	})
+	if awsErr, isAwsErr := err.(awserr.Error); isAwsErr && awsErr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	defer output.Body.Close()
+
+	contents, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return parseDoneMarker(contents)
+}
+
+func parseDoneMarker(contents []byte) (*DoneMarker, error) {
+	var marker DoneMarker
+	if err := json.Unmarshal(contents, &marker); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return &marker, nil
+}
+
+// Custom error types
+
+type InvalidDoneMarkerStore string
+
+func (location InvalidDoneMarkerStore) Error() string {
+	return fmt.Sprintf("Invalid done marker store %q. Expected a local folder or an S3 URL such as s3://bucket/prefix.", string(location))
+}
@@ -0,0 +1,192 @@
+package configstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+// The command that checks each module of a stack for drift with run-all drift.
+const DriftCommand = "drift"
+
+// ModuleDrift is the result of checking a single module for drift: whether the real infrastructure differs from the
+// state of the module, and the addresses of the resources that differ.
+type ModuleDrift struct {
+	Drifted   bool     `json:"drifted"`
+	Resources []string `json:"resources,omitempty"`
+}
+
+// The parts of the JSON representation of a refresh-only plan, as printed by `terraform show -json`, needed to list the
+// drifted resources.
+type driftPlanJSON struct {
+	ResourceDrift []struct {
+		Address string `json:"address"`
+		Change  struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+	} `json:"resource_drift"`
+}
+
+// parseDriftedResources returns the sorted addresses of the resources that changed outside of Terraform in the given
+// JSON representation of a plan.
+func parseDriftedResources(data []byte) ([]string, error) {
+	var plan driftPlanJSON
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	resources := []string{}
+	for _, resourceDrift := range plan.ResourceDrift {
+		actions := resourceDrift.Change.Actions
+		if len(actions) == 1 && actions[0] == "no-op" {
+			continue
+		}
+		resources = append(resources, resourceDrift.Address)
+	}
+	sort.Strings(resources)
+	return resources, nil
+}
+
+// DetectDrift checks the module of the given options for drift, by running `plan -refresh-only -detailed-exitcode`
+// through the RunTerragrunt function of the options. Exit code 0 means the module has not drifted and 2 means it has,
+// in which case the drifted resources are read from the saved plan. Any other result is returned as an error.
+func DetectDrift(terragruntOptions *options.TerragruntOptions) (*ModuleDrift, error) {
+	return detectDrift(terragruntOptions, terragruntOptions.RunTerragrunt)
+}
+
+func detectDrift(terragruntOptions *options.TerragruntOptions, runTerragrunt func(*options.TerragruntOptions) error) (*ModuleDrift, error) {
+	dir, err := os.MkdirTemp("", "terragrunt-drift")
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	defer os.RemoveAll(dir)
+
+	planOpts := terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+	planOpts.TerraformCommand = "plan"
+	planOpts.TerraformCliArgs = []string{"plan", "-refresh-only", "-detailed-exitcode", "-input=false"}
+	if len(terragruntOptions.TerraformCliArgs) > 1 {
+		planOpts.TerraformCliArgs = append(planOpts.TerraformCliArgs, terragruntOptions.TerraformCliArgs[1:]...)
+	}
+	planOpts.PlanJSONFile = filepath.Join(dir, "plan.json")
+
+	err = runTerragrunt(planOpts)
+	if err == nil {
+		return &ModuleDrift{}, nil
+	}
+	if exitCode, exitCodeErr := shell.GetExitCode(err); exitCodeErr != nil || exitCode != shell.DetailedExitCodeChanges {
+		return nil, err
+	}
+
+	drift := &ModuleDrift{Drifted: true}
+	data, err := os.ReadFile(planOpts.PlanJSONFile)
+	if err != nil {
+		terragruntOptions.Logger.Warnf("No saved plan found to list the drifted resources of %s: %v", terragruntOptions.WorkingDir, err)
+		return drift, nil
+	}
+	if drift.Resources, err = parseDriftedResources(data); err != nil {
+		terragruntOptions.Logger.Warnf("Failed to parse the plan of %s to list the drifted resources: %v", terragruntOptions.WorkingDir, err)
+	}
+	return drift, nil
+}
+
+// driftResults collects the drift of each module of a stack, keyed by module path, for run-all drift.
+type driftResults struct {
+	drifts map[string]*ModuleDrift
+	mutex  sync.Mutex
+}
+
+// prepareDriftDetection makes every module of the stack, instead of running the drift command as a terraform command,
+// check itself for drift and add the result to the returned driftResults. A drifted module finishes successfully, so
+// the modules that depend on it are checked too.
+func (stack *Stack) prepareDriftDetection() *driftResults {
+	results := &driftResults{drifts: map[string]*ModuleDrift{}}
+
+	for _, module := range stack.Modules {
+		runTerragrunt := module.TerragruntOptions.RunTerragrunt
+		modulePath := module.Path
+
+		module.TerragruntOptions.RunTerragrunt = func(opts *options.TerragruntOptions) error {
+			drift, err := detectDrift(opts, runTerragrunt)
+			if err != nil {
+				return err
+			}
+
+			results.mutex.Lock()
+			defer results.mutex.Unlock()
+			results.drifts[modulePath] = drift
+			return nil
+		}
+	}
+	return results
+}
+
+// driftedPaths returns the sorted paths of the modules that have drifted.
+func (results *driftResults) driftedPaths() []string {
+	results.mutex.Lock()
+	defer results.mutex.Unlock()
+
+	paths := []string{}
+	for path, drift := range results.drifts {
+		if drift.Drifted {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// writeDriftTable writes a table with whether each module that was run has drifted, and which resources, to the given
+// writer. Modules that could not be checked are listed with their run status instead.
+func (stack *Stack) writeDriftTable(w io.Writer, runningModules map[string]*runningModule, drifts map[string]*ModuleDrift) error {
+	paths := []string{}
+	for path := range runningModules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "MODULE\tDRIFT\tRESOURCES\t")
+
+	for _, path := range paths {
+		displayPath, err := filepath.Rel(stack.Path, path)
+		if err != nil {
+			displayPath = path
+		}
+
+		drift, hasDrift := drifts[path]
+		switch {
+		case !hasDrift:
+			fmt.Fprintf(table, "%s\t-\t%s\t\n", displayPath, newModuleReport(runningModules[path]).Status)
+		case drift.Drifted:
+			fmt.Fprintf(table, "%s\tyes\t%s\t\n", displayPath, strings.Join(drift.Resources, ", "))
+		default:
+			fmt.Fprintf(table, "%s\tno\t\t\n", displayPath)
+		}
+	}
+
+	return errors.WithStackTrace(table.Flush())
+}
+
+// Custom error types
+
+type DriftDetected struct {
+	Paths []string
+}
+
+func (err DriftDetected) Error() string {
+	return fmt.Sprintf("Drift detected in %d module(s): %s", len(err.Paths), strings.Join(err.Paths, ", "))
+}
+
+func (err DriftDetected) ExitStatus() (int, error) {
+	return shell.DetailedExitCodeChanges, nil
+}
@@ -0,0 +1,97 @@
+package configstack
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDriftedResources(t *testing.T) {
+	t.Parallel()
+
+	planJSON := `{
+  "format_version": "1.1",
+  "resource_drift": [
+    {"address": "aws_vpc.main", "change": {"actions": ["update"]}},
+    {"address": "aws_instance.a", "change": {"actions": ["delete"]}},
+    {"address": "aws_eip.a", "change": {"actions": ["no-op"]}}
+  ],
+  "resource_changes": []
+}`
+
+	resources, err := parseDriftedResources([]byte(planJSON))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aws_instance.a", "aws_vpc.main"}, resources)
+
+	resources, err = parseDriftedResources([]byte(`{"format_version": "1.1"}`))
+	require.NoError(t, err)
+	assert.Empty(t, resources)
+
+	_, err = parseDriftedResources([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestStackDriftDetection(t *testing.T) {
+	t.Parallel()
+
+	exitCode := func(code string) shell.ProcessExecutionError {
+		err := exec.Command("sh", "-c", "exit "+code).Run()
+		require.Error(t, err)
+		return shell.ProcessExecutionError{Err: err}
+	}
+
+	newModule := func(path string, planJSON string, toReturn error, dependencies ...*TerraformModule) *TerraformModule {
+		opts, err := options.NewTerragruntOptionsForTest(path)
+		require.NoError(t, err)
+		opts.TerraformCommand = DriftCommand
+		opts.TerraformCliArgs = []string{DriftCommand, "-var-file=prod.tfvars"}
+		opts.RunTerragrunt = func(opts *options.TerragruntOptions) error {
+			assert.Equal(t, []string{"plan", "-refresh-only", "-detailed-exitcode", "-input=false", "-var-file=prod.tfvars"}, opts.TerraformCliArgs)
+			if planJSON != "" {
+				if err := os.WriteFile(opts.PlanJSONFile, []byte(planJSON), 0644); err != nil {
+					return err
+				}
+			}
+			return toReturn
+		}
+		return &TerraformModule{Path: path, Dependencies: dependencies, TerragruntOptions: opts}
+	}
+
+	vpc := newModule("/stack/vpc", `{"resource_drift": [{"address": "aws_vpc.main", "change": {"actions": ["update"]}}]}`, exitCode("2"))
+	app := newModule("/stack/app", `{"resource_drift": []}`, nil, vpc)
+	db := newModule("/stack/db", "", exitCode("1"))
+
+	stack := &Stack{Path: "/stack", Modules: []*TerraformModule{vpc, app, db}}
+	results := stack.prepareDriftDetection()
+
+	runningModules, err := toRunningModules(stack.Modules, NormalOrder)
+	require.NoError(t, err)
+	assert.Error(t, runModules(context.Background(), runningModules, options.DefaultParallelism))
+
+	assert.Equal(t, map[string]*ModuleDrift{
+		"/stack/vpc": {Drifted: true, Resources: []string{"aws_vpc.main"}},
+		"/stack/app": {},
+	}, results.drifts)
+	assert.Equal(t, []string{"/stack/vpc"}, results.driftedPaths())
+	assert.NoError(t, runningModules["/stack/app"].Err)
+
+	var out bytes.Buffer
+	require.NoError(t, stack.writeDriftTable(&out, runningModules, results.drifts))
+	expected := "" +
+		"MODULE  DRIFT  RESOURCES     \n" +
+		"app     no                   \n" +
+		"db      -      failed        \n" +
+		"vpc     yes    aws_vpc.main  \n"
+	assert.Equal(t, expected, out.String())
+
+	exitStatus, err := shell.GetExitCode(DriftDetected{Paths: results.driftedPaths()})
+	require.NoError(t, err)
+	assert.Equal(t, 2, exitStatus)
+}
@@ -49,3 +49,107 @@ func checkForCyclesUsingDepthFirstSearch(module *TerraformModule, visitedPaths *
 
 	return nil
 }
+
+// FindModuleByPath returns the module of the stack in the given folder, or a ModuleNotFound error if the stack has no
+// module in that folder.
+func (stack *Stack) FindModuleByPath(path string) (*TerraformModule, error) {
+	for _, module := range stack.Modules {
+		if module.Path == path {
+			return module, nil
+		}
+	}
+	return nil, errors.WithStackTrace(ModuleNotFound(path))
+}
+
+// FindDependencies returns the modules that the given module depends on, sorted by path. If transitive is true, the
+// modules that those modules depend on are returned as well, recursively. Modules excluded from the stack are left out.
+func (stack *Stack) FindDependencies(module *TerraformModule, transitive bool) []*TerraformModule {
+	found := map[string]*TerraformModule{}
+
+	queue := module.Dependencies
+	for len(queue) > 0 {
+		dependency := queue[0]
+		queue = queue[1:]
+
+		if _, alreadyFound := found[dependency.Path]; alreadyFound {
+			continue
+		}
+		found[dependency.Path] = dependency
+
+		if transitive {
+			queue = append(queue, dependency.Dependencies...)
+		}
+	}
+
+	return sortedIncludedModules(found)
+}
+
+// FindDependents returns the modules of the stack that depend on the given module, sorted by path. If transitive is
+// true, the modules that depend on those modules are returned as well, recursively. Modules excluded from the stack are
+// left out.
+func (stack *Stack) FindDependents(module *TerraformModule, transitive bool) []*TerraformModule {
+	found := map[string]*TerraformModule{}
+
+	queue := []*TerraformModule{module}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, candidate := range stack.Modules {
+			if _, alreadyFound := found[candidate.Path]; alreadyFound {
+				continue
+			}
+			for _, dependency := range candidate.Dependencies {
+				if dependency.Path == current.Path {
+					found[candidate.Path] = candidate
+					if transitive {
+						queue = append(queue, candidate)
+					}
+					break
+				}
+			}
+		}
+	}
+
+	return sortedIncludedModules(found)
+}
+
+// GetRunGroups returns the given modules of the stack grouped in the order in which run-all would run them for the
+// given terraform command: the modules of a group can run concurrently, once the modules of all the previous groups
+// have finished. Modules that are not given, or are excluded from the stack, are left out.
+func (stack *Stack) GetRunGroups(terraformCommand string, modules []*TerraformModule) ([][]*TerraformModule, error) {
+	runGraph, err := stack.getModuleRunGraph(terraformCommand)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := map[string]bool{}
+	for _, module := range modules {
+		selected[module.Path] = true
+	}
+
+	groups := [][]*TerraformModule{}
+	for _, runGroup := range runGraph {
+		group := []*TerraformModule{}
+		for _, module := range runGroup {
+			if selected[module.Path] {
+				group = append(group, module)
+			}
+		}
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+// Returns the modules of the given map that are not excluded, sorted by path
+func sortedIncludedModules(modules map[string]*TerraformModule) []*TerraformModule {
+	included := []*TerraformModule{}
+	for _, path := range getSortedKeys(modules) {
+		if !modules[path].FlagExcluded {
+			included = append(included, modules[path])
+		}
+	}
+	return included
+}
@@ -1,10 +1,12 @@
 package configstack
 
 import (
+	goerrors "errors"
 	"testing"
 
 	"github.com/gruntwork-io/terragrunt/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCheckForCycles(t *testing.T) {
@@ -85,3 +87,53 @@ func TestCheckForCycles(t *testing.T) {
 		}
 	}
 }
+
+func TestStackGraphQueries(t *testing.T) {
+	t.Parallel()
+
+	// c -> b -> a
+	//      d -> a
+	// e -> d      (e is excluded)
+	a := &TerraformModule{Path: "a"}
+	b := &TerraformModule{Path: "b", Dependencies: []*TerraformModule{a}}
+	c := &TerraformModule{Path: "c", Dependencies: []*TerraformModule{b}}
+	d := &TerraformModule{Path: "d", Dependencies: []*TerraformModule{a}}
+	e := &TerraformModule{Path: "e", Dependencies: []*TerraformModule{d}, FlagExcluded: true}
+	stack := &Stack{Path: ".", Modules: []*TerraformModule{a, b, c, d, e}}
+
+	module, err := stack.FindModuleByPath("b")
+	require.NoError(t, err)
+	assert.Equal(t, b, module)
+
+	_, err = stack.FindModuleByPath("z")
+	var notFoundErr ModuleNotFound
+	assert.True(t, goerrors.As(err, &notFoundErr))
+
+	paths := func(modules []*TerraformModule) []string {
+		result := []string{}
+		for _, module := range modules {
+			result = append(result, module.Path)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"b", "d"}, paths(stack.FindDependents(a, false)))
+	assert.Equal(t, []string{"b", "c", "d"}, paths(stack.FindDependents(a, true)))
+	assert.Equal(t, []string{}, paths(stack.FindDependents(c, true)))
+	assert.Equal(t, []string{"b"}, paths(stack.FindDependencies(c, false)))
+	assert.Equal(t, []string{"a", "b"}, paths(stack.FindDependencies(c, true)))
+	assert.Equal(t, []string{"a", "d"}, paths(stack.FindDependencies(e, true)))
+
+	groups, err := stack.GetRunGroups("apply", stack.FindDependents(a, true))
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, []string{"b", "d"}, paths(groups[0]))
+	assert.Equal(t, []string{"c"}, paths(groups[1]))
+
+	groups, err = stack.GetRunGroups("destroy", stack.Modules)
+	require.NoError(t, err)
+	require.Len(t, groups, 3)
+	assert.Equal(t, []string{"c", "d"}, paths(groups[0]))
+	assert.Equal(t, []string{"b"}, paths(groups[1]))
+	assert.Equal(t, []string{"a"}, paths(groups[2]))
+}
@@ -1,45 +1,407 @@
 package configstack
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/util"
 )
 
+const (
+	// GraphFormatDot renders the dependency graph as a GraphViz digraph.
+	GraphFormatDot = "dot"
+
+	// GraphFormatMermaid renders the dependency graph as a Mermaid flowchart, which GitHub and GitLab render in markdown.
+	GraphFormatMermaid = "mermaid"
+
+	// GraphFormatJSON renders the dependency graph as a JSON document.
+	GraphFormatJSON = "json"
+)
+
+// The fill colour of the modules in each run status, when the graph is coloured with a run report.
+var graphStatusColors = map[ModuleRunStatus]string{
+	ModuleSucceeded:               "#90ee90",
+	ModuleFailed:                  "#fa8072",
+	ModuleTimedOutStatus:          "#fa8072",
+	ModuleSkippedDependencyFailed: "#ffa500",
+	ModuleCancelledStatus:         "#d3d3d3",
+	ModuleNotApprovedStatus:       "#ffa500",
+	ModuleExcluded:                "#d3d3d3",
+	ModuleAssumeAlreadyApplied:    "#d3d3d3",
+	ModuleOtherShard:              "#add8e6",
+}
+
+// graphNode is a module of the dependency graph, with the name and cluster under which it is shown.
+type graphNode struct {
+	Module *TerraformModule
+
+	// The path of the module relative to the folder of the stack
+	Name string
+
+	// The folder, relative to the folder of the stack, that the module is grouped in, or empty if the module is not
+	// grouped, because it is at the top of the stack or an external dependency.
+	Cluster string
+
+	// The result of the module in the run report that colours the graph, if any
+	Status ModuleRunStatus
+
+	// The folder of the stack, which is trimmed from the module paths
+	prefix string
+}
+
+// Returns the nodes of the graph of the given modules. The statuses of the modules, keyed by module path, may be nil.
+func newGraphNodes(terragruntOptions *options.TerragruntOptions, modules []*TerraformModule, statuses map[string]ModuleRunStatus) []*graphNode {
+	// all paths are relative to the TerragruntConfigPath
+	prefix := filepath.Dir(terragruntOptions.TerragruntConfigPath) + "/"
+
+	nodes := []*graphNode{}
+	for _, module := range modules {
+		node := &graphNode{
+			Module: module,
+			Name:   strings.TrimPrefix(module.Path, prefix),
+			Status: statuses[module.Path],
+			prefix: prefix,
+		}
+		if !module.IsExternal {
+			if dir := filepath.Dir(node.Name); dir != "." && !filepath.IsAbs(dir) {
+				node.Cluster = filepath.ToSlash(dir)
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// Returns the sorted folders of the clusters of the given nodes.
+func graphClusters(nodes []*graphNode) []string {
+	clusters := []string{}
+	for _, node := range nodes {
+		if node.Cluster != "" && !util.ListContainsElement(clusters, node.Cluster) {
+			clusters = append(clusters, node.Cluster)
+		}
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// WriteGraph writes the dependency graph of the given modules to the given writer in the given format: dot, mermaid or
+// json. If a run report is given, the modules are coloured by their result in that run.
+func WriteGraph(w io.Writer, terragruntOptions *options.TerragruntOptions, modules []*TerraformModule, format string, report *RunReport) error {
+	var statuses map[string]ModuleRunStatus
+	if report != nil {
+		statuses = map[string]ModuleRunStatus{}
+		for _, module := range report.Modules {
+			statuses[module.Path] = module.Status
+		}
+	}
+	nodes := newGraphNodes(terragruntOptions, modules, statuses)
+
+	switch format {
+	case "", GraphFormatDot:
+		return writeDotNodes(w, nodes)
+	case GraphFormatMermaid:
+		return writeMermaid(w, nodes)
+	case GraphFormatJSON:
+		return writeGraphJSON(w, nodes)
+	default:
+		return errors.WithStackTrace(UnsupportedGraphFormat(format))
+	}
+}
+
 // WriteDot is used to emit a GraphViz compatible definition
 // for a directed graph. It can be used to dump a .dot file.
 // This is a similar implementation to terraform's digraph https://github.com/hashicorp/terraform/blob/master/digraph/graphviz.go
 // adding some styling to modules that are excluded from the execution in *-all commands
 func WriteDot(w io.Writer, terragruntOptions *options.TerragruntOptions, modules []*TerraformModule) error {
+	return writeDotNodes(w, newGraphNodes(terragruntOptions, modules, nil))
+}
 
-	w.Write([]byte("digraph {\n"))
-	defer w.Write([]byte("}\n"))
+// Modules at the top of the stack are written first, each followed by its edges. The modules in subfolders are then
+// grouped in a cluster per folder, and their edges are written after the clusters.
+func writeDotNodes(w io.Writer, nodes []*graphNode) error {
+	var graph strings.Builder
+	graph.WriteString("digraph {\n")
 
-	// all paths are relative to the TerragruntConfigPath
-	prefix := filepath.Dir(terragruntOptions.TerragruntConfigPath) + "/"
+	// Dependencies through file dependencies, rather than through the dependencies of the config, are dashed
+	writeEdges := func(source *graphNode) {
+		for _, target := range source.Module.Dependencies {
+			style := ""
+			if source.Module.dependsThroughFilesOnly(target) {
+				style = " [style=dashed]"
+			}
+			fmt.Fprintf(&graph, "\t\"%s\" -> \"%s\"%s;\n",
+				source.Name,
+				nodeName(nodes, target),
+				style,
+			)
+		}
+	}
 
-	for _, source := range modules {
-		// apply a different coloring for excluded nodes
-		style := ""
-		if source.FlagExcluded {
-			style = fmt.Sprintf("[color=red]")
+	for _, node := range nodes {
+		if node.Cluster != "" {
+			continue
 		}
+		fmt.Fprintf(&graph, "\t\"%s\" %s;\n", node.Name, dotNodeStyle(node))
+		writeEdges(node)
+	}
 
-		nodeLine := fmt.Sprintf("\t\"%s\" %s;\n",
-			strings.TrimPrefix(source.Path, prefix), style)
+	for _, cluster := range graphClusters(nodes) {
+		fmt.Fprintf(&graph, "\tsubgraph \"cluster_%s\" {\n", cluster)
+		fmt.Fprintf(&graph, "\t\tlabel = \"%s\";\n", cluster)
+		for _, node := range nodes {
+			if node.Cluster == cluster {
+				fmt.Fprintf(&graph, "\t\t\"%s\" %s;\n", node.Name, dotNodeStyle(node))
+			}
+		}
+		graph.WriteString("\t}\n")
+	}
 
-		w.Write([]byte(nodeLine))
-		for _, target := range source.Dependencies {
-			line := fmt.Sprintf("\t\"%s\" -> \"%s\";\n",
-				strings.TrimPrefix(source.Path, prefix),
-				strings.TrimPrefix(target.Path, prefix),
-			)
-			w.Write([]byte(line))
+	for _, node := range nodes {
+		if node.Cluster != "" {
+			writeEdges(node)
+		}
+	}
+
+	graph.WriteString("}\n")
+
+	// The graph is built in memory and written at once, so that a failed write is reported once
+	_, err := io.WriteString(w, graph.String())
+	return errors.WithStackTrace(err)
+}
+
+// Returns the name under which the given module is shown in the graph. Dependencies that are not in the graph are
+// named by their path relative to the folder of the stack, like the modules in the graph.
+func nodeName(nodes []*graphNode, module *TerraformModule) string {
+	for _, node := range nodes {
+		if node.Module.Path == module.Path {
+			return node.Name
+		}
+	}
+	if len(nodes) > 0 {
+		return strings.TrimPrefix(module.Path, nodes[0].prefix)
+	}
+	return module.Path
+}
+
+// Returns the GraphViz attributes of the given node:
+//   - excluded modules are red
+//   - skipped modules are dashed and modules assumed to be already applied are dotted
+//   - external dependencies are boxes
+//   - modules with prevent_destroy have a double border
+//   - modules are filled with the colour of their run status, if any
+func dotNodeStyle(node *graphNode) string {
+	module := node.Module
+
+	attributes := []string{}
+	if module.FlagExcluded {
+		attributes = append(attributes, "color=red")
+	}
+	if module.IsExternal {
+		attributes = append(attributes, "shape=box")
+	}
+	if module.Config.PreventDestroy != nil && *module.Config.PreventDestroy {
+		attributes = append(attributes, "peripheries=2")
+	}
+
+	styles := []string{}
+	if module.Config.Skip {
+		styles = append(styles, "dashed")
+	} else if module.AssumeAlreadyApplied {
+		styles = append(styles, "dotted")
+	}
+	fillColor, hasStatus := graphStatusColors[node.Status]
+	if hasStatus {
+		styles = append(styles, "filled")
+	}
+	if len(styles) > 0 {
+		attributes = append(attributes, fmt.Sprintf("style=\"%s\"", strings.Join(styles, ",")))
+	}
+	if hasStatus {
+		attributes = append(attributes, fmt.Sprintf("fillcolor=\"%s\"", fillColor))
+	}
+
+	if len(attributes) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(attributes, ", ") + "]"
+}
+
+// The Mermaid classes that style the modules, in the order in which they are defined.
+var mermaidClassDefs = []struct {
+	Name  string
+	Style string
+}{
+	{"excluded", "stroke:#ff0000"},
+	{"skip", "stroke-dasharray:5 5"},
+	{"assumeAlreadyApplied", "stroke-dasharray:2 2"},
+	{"preventDestroy", "stroke-width:4px"},
+}
+
+// Writes the graph as a Mermaid flowchart. Nodes are identified by their position, as module paths are not valid
+// Mermaid ids, and labelled with their name. External dependencies use the subroutine shape, and dependencies through
+// file dependencies are dotted links.
+func writeMermaid(w io.Writer, nodes []*graphNode) error {
+	ids := map[string]string{}
+	for n, node := range nodes {
+		ids[node.Module.Path] = fmt.Sprintf("n%d", n)
+	}
+
+	lines := []string{"flowchart TD"}
+	nodeLine := func(indent string, node *graphNode) string {
+		label := strings.ReplaceAll(node.Name, "\"", "#quot;")
+		if node.Module.IsExternal {
+			return fmt.Sprintf("%s%s[[\"%s\"]]", indent, ids[node.Module.Path], label)
+		}
+		return fmt.Sprintf("%s%s[\"%s\"]", indent, ids[node.Module.Path], label)
+	}
+
+	for _, node := range nodes {
+		if node.Cluster == "" {
+			lines = append(lines, nodeLine("\t", node))
+		}
+	}
+	for n, cluster := range graphClusters(nodes) {
+		lines = append(lines, fmt.Sprintf("\tsubgraph c%d [\"%s\"]", n, cluster))
+		for _, node := range nodes {
+			if node.Cluster == cluster {
+				lines = append(lines, nodeLine("\t\t", node))
+			}
+		}
+		lines = append(lines, "\tend")
+	}
+
+	for _, node := range nodes {
+		for _, target := range node.Module.Dependencies {
+			targetID, isInGraph := ids[target.Path]
+			if !isInGraph {
+				continue
+			}
+			arrow := "-->"
+			if node.Module.dependsThroughFilesOnly(target) {
+				arrow = "-.->"
+			}
+			lines = append(lines, fmt.Sprintf("\t%s %s %s", ids[node.Module.Path], arrow, targetID))
+		}
+	}
+
+	usedClasses := map[string]bool{}
+	statusClasses := map[ModuleRunStatus]bool{}
+	for _, node := range nodes {
+		id := ids[node.Module.Path]
+		for _, class := range mermaidNodeClasses(node) {
+			lines = append(lines, fmt.Sprintf("\tclass %s %s", id, class))
+			usedClasses[class] = true
+		}
+		if _, hasStatus := graphStatusColors[node.Status]; hasStatus {
+			lines = append(lines, fmt.Sprintf("\tclass %s %s", id, mermaidStatusClass(node.Status)))
+			statusClasses[node.Status] = true
+		}
+	}
+
+	for _, classDef := range mermaidClassDefs {
+		if usedClasses[classDef.Name] {
+			lines = append(lines, fmt.Sprintf("\tclassDef %s %s", classDef.Name, classDef.Style))
 		}
 	}
+	statuses := []string{}
+	for status := range statusClasses {
+		statuses = append(statuses, string(status))
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		lines = append(lines, fmt.Sprintf("\tclassDef %s fill:%s", mermaidStatusClass(ModuleRunStatus(status)), graphStatusColors[ModuleRunStatus(status)]))
+	}
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return errors.WithStackTrace(err)
+}
+
+// Returns the Mermaid classes, other than the run status, of the given node.
+func mermaidNodeClasses(node *graphNode) []string {
+	module := node.Module
+
+	classes := []string{}
+	if module.FlagExcluded {
+		classes = append(classes, "excluded")
+	}
+	if module.Config.Skip {
+		classes = append(classes, "skip")
+	} else if module.AssumeAlreadyApplied {
+		classes = append(classes, "assumeAlreadyApplied")
+	}
+	if module.Config.PreventDestroy != nil && *module.Config.PreventDestroy {
+		classes = append(classes, "preventDestroy")
+	}
+	return classes
+}
+
+// Returns the Mermaid class of the given run status, such as statusTimedOut for timed-out.
+func mermaidStatusClass(status ModuleRunStatus) string {
+	class := "status"
+	for _, word := range strings.Split(string(status), "-") {
+		if word != "" {
+			class += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return class
+}
+
+// graphJSONModule is a module in the JSON representation of the dependency graph.
+type graphJSONModule struct {
+	Path                 string          `json:"path"`
+	Dependencies         []string        `json:"dependencies"`
+	FileDependencies     []string        `json:"file_dependencies,omitempty"`
+	Excluded             bool            `json:"excluded"`
+	Skip                 bool            `json:"skip"`
+	AssumeAlreadyApplied bool            `json:"assume_already_applied"`
+	External             bool            `json:"external"`
+	PreventDestroy       bool            `json:"prevent_destroy"`
+	Status               ModuleRunStatus `json:"status,omitempty"`
+}
+
+func writeGraphJSON(w io.Writer, nodes []*graphNode) error {
+	modules := []graphJSONModule{}
+	for _, node := range nodes {
+		module := node.Module
+
+		dependencies := []string{}
+		for _, dependency := range module.Dependencies {
+			dependencies = append(dependencies, nodeName(nodes, dependency))
+		}
+
+		fileDependencies := []string{}
+		for _, fileDependency := range module.FileDependencies {
+			fileDependencies = append(fileDependencies, strings.TrimPrefix(fileDependency, node.prefix))
+		}
+
+		modules = append(modules, graphJSONModule{
+			Path:                 node.Name,
+			Dependencies:         dependencies,
+			FileDependencies:     fileDependencies,
+			Excluded:             module.FlagExcluded,
+			Skip:                 module.Config.Skip,
+			AssumeAlreadyApplied: module.AssumeAlreadyApplied,
+			External:             module.IsExternal,
+			PreventDestroy:       module.Config.PreventDestroy != nil && *module.Config.PreventDestroy,
+			Status:               node.Status,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.WithStackTrace(encoder.Encode(map[string]interface{}{"modules": modules}))
+}
+
+// Custom error types
+
+type UnsupportedGraphFormat string
 
-	return nil
+func (format UnsupportedGraphFormat) Error() string {
+	return fmt.Sprintf("Unsupported graph format %q. Supported formats are: %s, %s, %s", string(format), GraphFormatDot, GraphFormatMermaid, GraphFormatJSON)
 }
@@ -2,11 +2,15 @@ package configstack
 
 import (
 	"bytes"
+	"encoding/json"
+	goerrors "errors"
 	"strings"
 	"testing"
 
+	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGraph(t *testing.T) {
@@ -63,14 +67,23 @@ digraph {
 	"b" ;
 	"c" ;
 	"d" ;
-	"alpha/beta/gamma/e" ;
+	subgraph "cluster_alpha" {
+		label = "alpha";
+		"alpha/g" ;
+	}
+	subgraph "cluster_alpha/beta" {
+		label = "alpha/beta";
+		"alpha/beta/h" ;
+	}
+	subgraph "cluster_alpha/beta/gamma" {
+		label = "alpha/beta/gamma";
+		"alpha/beta/gamma/e" ;
+		"alpha/beta/gamma/f" ;
+	}
 	"alpha/beta/gamma/e" -> "a";
-	"alpha/beta/gamma/f" ;
 	"alpha/beta/gamma/f" -> "a";
 	"alpha/beta/gamma/f" -> "b";
-	"alpha/g" ;
 	"alpha/g" -> "alpha/beta/gamma/e";
-	"alpha/beta/h" ;
 	"alpha/beta/h" -> "alpha/g";
 	"alpha/beta/h" -> "alpha/beta/gamma/f";
 	"alpha/beta/h" -> "c";
@@ -113,3 +126,156 @@ digraph {
 `)
 	assert.True(t, strings.Contains(stdout.String(), expected))
 }
+
+// Returns modules with every kind of node attribute, and a run report for some of them.
+func newGraphAttributesModules() ([]*TerraformModule, *RunReport) {
+	preventDestroy := true
+
+	vpc := &TerraformModule{Path: "/config/vpc", Config: config.TerragruntConfig{PreventDestroy: &preventDestroy}}
+	shared := &TerraformModule{Path: "/shared/dns", IsExternal: true, AssumeAlreadyApplied: true}
+	db := &TerraformModule{Path: "/config/data/db", Dependencies: []*TerraformModule{vpc, shared}}
+	cache := &TerraformModule{Path: "/config/data/cache", FlagExcluded: true, Config: config.TerragruntConfig{Skip: true}}
+	app := &TerraformModule{Path: "/config/app", Dependencies: []*TerraformModule{db, cache}}
+
+	report := &RunReport{Modules: []*ModuleReport{
+		{Path: "/config/vpc", Status: ModuleSucceeded},
+		{Path: "/config/data/db", Status: ModuleFailed},
+		{Path: "/config/app", Status: ModuleSkippedDependencyFailed},
+	}}
+
+	return []*TerraformModule{vpc, shared, db, cache, app}, report
+}
+
+func TestGraphNodeAttributes(t *testing.T) {
+	modules, report := newGraphAttributesModules()
+
+	var stdout bytes.Buffer
+	terragruntOptions, _ := options.NewTerragruntOptionsWithConfigPath("/config/terragrunt.hcl")
+	require.NoError(t, WriteGraph(&stdout, terragruntOptions, modules, GraphFormatDot, report))
+	expected := strings.TrimSpace(`
+digraph {
+	"vpc" [peripheries=2, style="filled", fillcolor="#90ee90"];
+	"/shared/dns" [shape=box, style="dotted"];
+	"app" [style="filled", fillcolor="#ffa500"];
+	"app" -> "data/db";
+	"app" -> "data/cache";
+	subgraph "cluster_data" {
+		label = "data";
+		"data/db" [style="filled", fillcolor="#fa8072"];
+		"data/cache" [color=red, style="dashed"];
+	}
+	"data/db" -> "vpc";
+	"data/db" -> "/shared/dns";
+}
+`)
+	assert.Equal(t, expected, strings.TrimSpace(stdout.String()))
+}
+
+func TestGraphMermaid(t *testing.T) {
+	modules, report := newGraphAttributesModules()
+
+	var stdout bytes.Buffer
+	terragruntOptions, _ := options.NewTerragruntOptionsWithConfigPath("/config/terragrunt.hcl")
+	require.NoError(t, WriteGraph(&stdout, terragruntOptions, modules, GraphFormatMermaid, report))
+	expected := strings.TrimSpace(`
+flowchart TD
+	n0["vpc"]
+	n1[["/shared/dns"]]
+	n4["app"]
+	subgraph c0 ["data"]
+		n2["data/db"]
+		n3["data/cache"]
+	end
+	n2 --> n0
+	n2 --> n1
+	n4 --> n2
+	n4 --> n3
+	class n0 preventDestroy
+	class n0 statusSucceeded
+	class n1 assumeAlreadyApplied
+	class n2 statusFailed
+	class n3 excluded
+	class n3 skip
+	class n4 statusSkipped
+	classDef excluded stroke:#ff0000
+	classDef skip stroke-dasharray:5 5
+	classDef assumeAlreadyApplied stroke-dasharray:2 2
+	classDef preventDestroy stroke-width:4px
+	classDef statusFailed fill:#fa8072
+	classDef statusSkipped fill:#ffa500
+	classDef statusSucceeded fill:#90ee90
+`)
+	assert.Equal(t, expected, strings.TrimSpace(stdout.String()))
+}
+
+func TestGraphJSON(t *testing.T) {
+	modules, report := newGraphAttributesModules()
+
+	var stdout bytes.Buffer
+	terragruntOptions, _ := options.NewTerragruntOptionsWithConfigPath("/config/terragrunt.hcl")
+	require.NoError(t, WriteGraph(&stdout, terragruntOptions, modules, GraphFormatJSON, report))
+
+	var graph struct {
+		Modules []graphJSONModule `json:"modules"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &graph))
+	require.Len(t, graph.Modules, 5)
+
+	assert.Equal(t, graphJSONModule{Path: "vpc", Dependencies: []string{}, PreventDestroy: true, Status: ModuleSucceeded}, graph.Modules[0])
+	assert.Equal(t, graphJSONModule{Path: "/shared/dns", Dependencies: []string{}, AssumeAlreadyApplied: true, External: true}, graph.Modules[1])
+	assert.Equal(t, graphJSONModule{Path: "data/db", Dependencies: []string{"vpc", "/shared/dns"}, Status: ModuleFailed}, graph.Modules[2])
+	assert.Equal(t, graphJSONModule{Path: "data/cache", Dependencies: []string{}, Excluded: true, Skip: true}, graph.Modules[3])
+}
+
+func TestWriteGraphUnsupportedFormat(t *testing.T) {
+	modules, _ := newGraphAttributesModules()
+
+	var stdout bytes.Buffer
+	terragruntOptions, _ := options.NewTerragruntOptionsWithConfigPath("/config/terragrunt.hcl")
+	err := WriteGraph(&stdout, terragruntOptions, modules, "svg", nil)
+
+	var formatErr UnsupportedGraphFormat
+	assert.True(t, goerrors.As(err, &formatErr))
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(_ []byte) (int, error) {
+	return 0, goerrors.New("write failed")
+}
+
+func TestWriteGraphWriteError(t *testing.T) {
+	modules, _ := newGraphAttributesModules()
+
+	terragruntOptions, _ := options.NewTerragruntOptionsWithConfigPath("/config/terragrunt.hcl")
+	for _, format := range []string{GraphFormatDot, GraphFormatMermaid, GraphFormatJSON} {
+		assert.ErrorContains(t, WriteGraph(failingWriter{}, terragruntOptions, modules, format, nil), "write failed", format)
+	}
+}
+
+func TestGraphFileDependencies(t *testing.T) {
+	vpc := &TerraformModule{Path: "/config/vpc"}
+	app := &TerraformModule{
+		Path:             "/config/app",
+		Dependencies:     []*TerraformModule{vpc},
+		FileDependencies: []string{"/config/vpc/outputs.hcl"},
+	}
+	modules := []*TerraformModule{vpc, app}
+	terragruntOptions, _ := options.NewTerragruntOptionsWithConfigPath("/config/terragrunt.hcl")
+
+	var dot bytes.Buffer
+	require.NoError(t, WriteGraph(&dot, terragruntOptions, modules, GraphFormatDot, nil))
+	assert.Contains(t, dot.String(), "\t\"app\" -> \"vpc\" [style=dashed];\n")
+
+	var mermaid bytes.Buffer
+	require.NoError(t, WriteGraph(&mermaid, terragruntOptions, modules, GraphFormatMermaid, nil))
+	assert.Contains(t, mermaid.String(), "\tn1 -.-> n0\n")
+
+	var graph struct {
+		Modules []graphJSONModule `json:"modules"`
+	}
+	var out bytes.Buffer
+	require.NoError(t, WriteGraph(&out, terragruntOptions, modules, GraphFormatJSON, nil))
+	require.NoError(t, json.Unmarshal(out.Bytes(), &graph))
+	assert.Equal(t, graphJSONModule{Path: "app", Dependencies: []string{"vpc"}, FileDependencies: []string{"vpc/outputs.hcl"}}, graph.Modules[1])
+}
@@ -0,0 +1,66 @@
+package configstack
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// outputJsonGetter returns the outputs of the module with the given config, as printed by `terraform output -json`. It
+// is config.GetOutputJson, except in tests.
+type outputJsonGetter func(targetConfig string, terragruntOptions *options.TerragruntOptions) ([]byte, error)
+
+// mergedOutputs collects the outputs of the modules of a stack, keyed by the path of each module relative to the stack,
+// for run-all output --terragrunt-merge-json.
+type mergedOutputs struct {
+	outputs map[string]json.RawMessage
+	mutex   sync.Mutex
+}
+
+// prepareMergedOutputs makes every module of the stack, instead of running terraform output, read its outputs with the
+// given function and add them to the returned mergedOutputs.
+func (stack *Stack) prepareMergedOutputs(getOutputJson outputJsonGetter) *mergedOutputs {
+	merged := &mergedOutputs{outputs: map[string]json.RawMessage{}}
+
+	for _, module := range stack.Modules {
+		key, err := util.GetPathRelativeTo(module.Path, stack.Path)
+		if err != nil {
+			key = module.Path
+		}
+
+		module.TerragruntOptions.RunTerragrunt = func(opts *options.TerragruntOptions) error {
+			jsonBytes, err := getOutputJson(opts.TerragruntConfigPath, opts)
+			if err != nil {
+				return err
+			}
+			var outputs map[string]json.RawMessage
+			if err := json.Unmarshal(jsonBytes, &outputs); err != nil {
+				return errors.WithStackTrace(config.TerragruntOutputParsingError{Path: opts.TerragruntConfigPath, Err: err})
+			}
+
+			merged.mutex.Lock()
+			defer merged.mutex.Unlock()
+			merged.outputs[key] = jsonBytes
+			return nil
+		}
+	}
+	return merged
+}
+
+// Write writes the collected outputs to the given writer as a single JSON object.
+func (merged *mergedOutputs) Write(w io.Writer) error {
+	merged.mutex.Lock()
+	defer merged.mutex.Unlock()
+
+	out, err := json.MarshalIndent(merged.outputs, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	_, err = w.Write(append(out, '\n'))
+	return errors.WithStackTrace(err)
+}
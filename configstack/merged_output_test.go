@@ -0,0 +1,72 @@
+package configstack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStackMergedOutputs(t *testing.T) {
+	t.Parallel()
+
+	outputs := map[string]string{
+		"/stack/vpc/terragrunt.hcl":          `{"vpc_id": {"sensitive": false, "type": "string", "value": "vpc-123"}}`,
+		"/stack/services/app/terragrunt.hcl": `{}`,
+	}
+	getOutputJson := func(targetConfig string, _ *options.TerragruntOptions) ([]byte, error) {
+		return []byte(outputs[targetConfig]), nil
+	}
+
+	vpcRan := false
+	vpc := &TerraformModule{Path: "/stack/vpc", TerragruntOptions: optionsWithMockTerragruntCommand(t, "/stack/vpc/terragrunt.hcl", nil, &vpcRan)}
+	appRan := false
+	app := &TerraformModule{Path: "/stack/services/app", Dependencies: []*TerraformModule{vpc}, TerragruntOptions: optionsWithMockTerragruntCommand(t, "/stack/services/app/terragrunt.hcl", nil, &appRan)}
+
+	stack := &Stack{Path: "/stack", Modules: []*TerraformModule{vpc, app}}
+	merged := stack.prepareMergedOutputs(getOutputJson)
+
+	runningModules, err := toRunningModules(stack.Modules, NormalOrder)
+	require.NoError(t, err)
+	require.NoError(t, runModules(context.Background(), runningModules, options.DefaultParallelism))
+	assert.False(t, vpcRan)
+	assert.False(t, appRan)
+
+	var out bytes.Buffer
+	require.NoError(t, merged.Write(&out))
+
+	var actual map[string]map[string]struct {
+		Value interface{} `json:"value"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &actual))
+	assert.Len(t, actual, 2)
+	assert.Equal(t, "vpc-123", actual["vpc"]["vpc_id"].Value)
+	assert.Empty(t, actual[filepath.ToSlash(filepath.Join("services", "app"))])
+}
+
+func TestStackMergedOutputsInvalidJson(t *testing.T) {
+	t.Parallel()
+
+	getOutputJson := func(targetConfig string, _ *options.TerragruntOptions) ([]byte, error) {
+		return []byte("Enabling CSM"), nil
+	}
+
+	ran := false
+	module := &TerraformModule{Path: "/stack/a", TerragruntOptions: optionsWithMockTerragruntCommand(t, "/stack/a/terragrunt.hcl", nil, &ran)}
+	stack := &Stack{Path: "/stack", Modules: []*TerraformModule{module}}
+	stack.prepareMergedOutputs(getOutputJson)
+
+	runningModules, err := toRunningModules(stack.Modules, NormalOrder)
+	require.NoError(t, err)
+	err = runModules(context.Background(), runningModules, options.DefaultParallelism)
+
+	var parsingErr config.TerragruntOutputParsingError
+	assert.True(t, errors.As(err, &parsingErr))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/pkg/errors"
 	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/terraform"
 	"github.com/gruntwork-io/terragrunt/util"
 	zglob "github.com/mattn/go-zglob"
 )
@@ -27,6 +28,15 @@ type TerraformModule struct {
 	TerragruntOptions    *options.TerragruntOptions
 	AssumeAlreadyApplied bool
 	FlagExcluded         bool
+
+	// True if the module is a dependency from outside the folder in which the stack was found
+	IsExternal bool
+
+	// The canonical paths of the files and folders, other than the configs it includes, that the config of this module
+	// depends on: the configs it reads with read_terragrunt_config, the files it decrypts with sops_decrypt_file and the
+	// folder of its terraform source, if it is local. The module runs after the other modules of the stack whose folder
+	// contains one of them.
+	FileDependencies []string
 }
 
 // Render this module as a human-readable string
@@ -74,7 +84,14 @@ func ResolveTerraformModules(terragruntConfigPaths []string, terragruntOptions *
 		return []*TerraformModule{}, err
 	}
 
-	finalModules, err := flagModulesThatDontInclude(includedModulesWithExcluded, terragruntOptions)
+	modulesWithConfigExcluded := flagExcludedByConfig(includedModulesWithExcluded, terragruntOptions)
+
+	modulesThatInclude, err := flagModulesThatDontInclude(modulesWithConfigExcluded, terragruntOptions)
+	if err != nil {
+		return []*TerraformModule{}, err
+	}
+
+	finalModules, err := flagModulesNotAffectedByChanges(modulesThatInclude, terragruntOptions)
 	if err != nil {
 		return []*TerraformModule{}, err
 	}
@@ -144,6 +161,36 @@ func flagExcludedDirs(modules []*TerraformModule, terragruntOptions *options.Ter
 	return modules, nil
 }
 
+// flagExcludedByConfig iterates over a module slice and flags all entries as excluded, whose exclude block excludes
+// them from the current command. The dependencies of the modules whose exclude block sets exclude_dependencies are
+// excluded as well.
+func flagExcludedByConfig(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) []*TerraformModule {
+	var excludeWithDependencies func(module *TerraformModule)
+	excludeWithDependencies = func(module *TerraformModule) {
+		for _, dependency := range module.Dependencies {
+			if !dependency.FlagExcluded {
+				terragruntOptions.Logger.Debugf("Excluding module %s, which is a dependency of excluded module %s", dependency.Path, module.Path)
+				dependency.FlagExcluded = true
+				excludeWithDependencies(dependency)
+			}
+		}
+	}
+
+	for _, module := range modules {
+		if !module.Config.Exclude.IsExcluded(terragruntOptions.TerraformCommand) {
+			continue
+		}
+
+		terragruntOptions.Logger.Debugf("Module %s is excluded from %s by its exclude block", module.Path, terragruntOptions.TerraformCommand)
+		module.FlagExcluded = true
+		if module.Config.Exclude.ExcludeDependencies {
+			excludeWithDependencies(module)
+		}
+	}
+
+	return modules
+}
+
 // flagIncludedDirs iterates over a module slice and flags all entries not in the list specified via the terragrunt-include-dir CLI flag  as excluded.
 func flagIncludedDirs(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) ([]*TerraformModule, error) {
 
@@ -224,9 +271,9 @@ func findModuleinPath(module *TerraformModule, targetDirs []string) bool {
 	return false
 }
 
-// flagModulesThatDontInclude iterates over a module slice and flags all modules that don't include at least one file in
-// the specified include list on the TerragruntOptions ModulesThatInclude attribute. Flagged modules will be filtered
-// out of the set.
+// flagModulesThatDontInclude iterates over a module slice and flags all modules that don't include, or have as file
+// dependency, at least one file in the specified include list on the TerragruntOptions ModulesThatInclude attribute.
+// Flagged modules will be filtered out of the set.
 func flagModulesThatDontInclude(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) ([]*TerraformModule, error) {
 
 	// If no ModulesThatInclude is specified return the modules list instantly
@@ -251,20 +298,12 @@ func flagModulesThatDontInclude(modules []*TerraformModule, terragruntOptions *o
 			continue
 		}
 
-		// Mark modules that don't include any of the specified paths as excluded. To do this, we first flag the module
-		// as excluded, and if it includes any path in the set, we set the exclude flag back to false.
-		module.FlagExcluded = true
-		for _, includeConfig := range module.Config.ProcessedIncludes {
-			// resolve include config to canonical path to compare with modulesThatIncludeCanonicalPath
-			// https://github.com/gruntwork-io/terragrunt/issues/1944
-			canonicalPath, err := util.CanonicalPath(includeConfig.Path, module.Path)
-			if err != nil {
-				return nil, err
-			}
-			if util.ListContainsElement(modulesThatIncludeCanonicalPath, canonicalPath) {
-				module.FlagExcluded = false
-			}
+		// Mark modules that don't include or depend on any of the specified paths as excluded.
+		includesPath, err := moduleIncludesAnyPath(module, modulesThatIncludeCanonicalPath)
+		if err != nil {
+			return nil, err
 		}
+		module.FlagExcluded = !includesPath
 
 		// Also search module dependencies and exclude if the dependency path doesn't include any of the specified
 		// paths, using a similar logic.
@@ -273,30 +312,43 @@ func flagModulesThatDontInclude(modules []*TerraformModule, terragruntOptions *o
 				continue
 			}
 
-			dependency.FlagExcluded = true
-			for _, includeConfig := range dependency.Config.ProcessedIncludes {
-				canonicalPath, err := util.CanonicalPath(includeConfig.Path, module.Path)
-				if err != nil {
-					return nil, err
-				}
-				if util.ListContainsElement(modulesThatIncludeCanonicalPath, canonicalPath) {
-					dependency.FlagExcluded = false
-				}
+			includesPath, err := moduleIncludesAnyPath(dependency, modulesThatIncludeCanonicalPath)
+			if err != nil {
+				return nil, err
 			}
+			dependency.FlagExcluded = !includesPath
 		}
 	}
 
 	return modules, nil
 }
 
+// Returns true if one of the given canonical paths is a config the given module includes or one of its file
+// dependencies, or is in a folder that is one of its file dependencies.
+// https://github.com/gruntwork-io/terragrunt/issues/1944
+func moduleIncludesAnyPath(module *TerraformModule, canonicalPaths []string) (bool, error) {
+	affectingFiles, err := filesAffectingModule(module)
+	if err != nil {
+		return false, err
+	}
+	for _, path := range canonicalPaths {
+		if isAffectedByFile(affectingFiles, path) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Go through each of the given Terragrunt configuration files and resolve the module that configuration file represents
 // into a TerraformModule struct. Note that this method will NOT fill in the Dependencies field of the TerraformModule
 // struct (see the crosslinkDependencies method for that). Return a map from module path to TerraformModule struct.
 func resolveModules(canonicalTerragruntConfigPaths []string, terragruntOptions *options.TerragruntOptions, childTerragruntConfig *config.TerragruntConfig, howTheseModulesWereFound string) (map[string]*TerraformModule, error) {
 	moduleMap := map[string]*TerraformModule{}
 
+	cache := openDiscoveryCache(terragruntOptions)
+
 	for _, terragruntConfigPath := range canonicalTerragruntConfigPaths {
-		module, err := resolveTerraformModule(terragruntConfigPath, terragruntOptions, childTerragruntConfig, howTheseModulesWereFound)
+		module, err := resolveTerraformModule(terragruntConfigPath, terragruntOptions, childTerragruntConfig, howTheseModulesWereFound, cache)
 		if err != nil {
 			return moduleMap, err
 		}
@@ -305,13 +357,20 @@ func resolveModules(canonicalTerragruntConfigPaths []string, terragruntOptions *
 		}
 	}
 
+	if cache != nil {
+		if err := cache.Save(); err != nil {
+			terragruntOptions.Logger.Warnf("Failed to save the discovery cache %s: %v", terragruntOptions.DiscoveryCacheFile, err)
+		}
+	}
+
 	return moduleMap, nil
 }
 
 // Create a TerraformModule struct for the Terraform module specified by the given Terragrunt configuration file path.
 // Note that this method will NOT fill in the Dependencies field of the TerraformModule struct (see the
-// crosslinkDependencies method for that).
-func resolveTerraformModule(terragruntConfigPath string, terragruntOptions *options.TerragruntOptions, childTerragruntConfig *config.TerragruntConfig, howThisModuleWasFound string) (*TerraformModule, error) {
+// crosslinkDependencies method for that). If a discovery cache is given, the config is only parsed if it has changed
+// since it was cached.
+func resolveTerraformModule(terragruntConfigPath string, terragruntOptions *options.TerragruntOptions, childTerragruntConfig *config.TerragruntConfig, howThisModuleWasFound string, cache *discoveryCache) (*TerraformModule, error) {
 	modulePath, err := util.CanonicalPath(filepath.Dir(terragruntConfigPath), ".")
 	if err != nil {
 		return nil, err
@@ -334,24 +393,73 @@ func resolveTerraformModule(terragruntConfigPath string, terragruntOptions *opti
 		opts.TerragruntConfigPath = terragruntOptions.OriginalTerragruntConfigPath
 	}
 
-	// We only partially parse the config, only using the pieces that we need in this section. This config will be fully
-	// parsed at a later stage right before the action is run. This is to delay interpolation of functions until right
-	// before we call out to terraform.
-	terragruntConfig, err := config.PartialParseConfigFile(
-		terragruntConfigPath,
-		opts,
-		includeConfig,
-		[]config.PartialDecodeSectionType{
-			// Need for initializing the modules
-			config.TerraformSource,
-
-			// Need for parsing out the dependencies
-			config.DependenciesBlock,
-			config.DependencyBlock,
-		},
-	)
+	// Configs parsed as a parent of another config depend on that config, so they are not cached
+	if includeConfig != nil {
+		cache = nil
+	}
+
+	var terragruntConfig *config.TerragruntConfig
+	var readFilePaths []string
+	if cache != nil {
+		var hasExclude bool
+		terragruntConfig, readFilePaths, hasExclude = cache.Get(terragruntConfigPath)
+		if terragruntConfig != nil {
+			terragruntOptions.Logger.Debugf("Using the cached config of module %s", modulePath)
+		}
+
+		// The exclude block is not cached, as its condition can depend on the command being run, so it is parsed again
+		if terragruntConfig != nil && hasExclude {
+			excludeConfig, err := config.PartialParseConfigFile(terragruntConfigPath, opts, includeConfig, []config.PartialDecodeSectionType{config.TerragruntExclude})
+			if err != nil {
+				return nil, errors.WithStackTrace(ErrorProcessingModule{UnderlyingError: err, HowThisModuleWasFound: howThisModuleWasFound, ModulePath: terragruntConfigPath})
+			}
+			terragruntConfig.Exclude = excludeConfig.Exclude
+		}
+	}
+
+	if terragruntConfig == nil {
+		// Record the files read by this module, which are file dependencies of the module, and which make its cached
+		// config be parsed again when one of them changes.
+		opts.FileReadTracker = options.NewFileReadTracker()
+
+		// We only partially parse the config, only using the pieces that we need in this section. This config will be fully
+		// parsed at a later stage right before the action is run. This is to delay interpolation of functions until right
+		// before we call out to terraform.
+		var err error
+		terragruntConfig, err = config.PartialParseConfigFile(
+			terragruntConfigPath,
+			opts,
+			includeConfig,
+			[]config.PartialDecodeSectionType{
+				// Need for initializing the modules
+				config.TerraformSource,
+
+				// Need for parsing out the dependencies
+				config.DependenciesBlock,
+				config.DependencyBlock,
+
+				// Need for limiting how many modules of a concurrency group run at once
+				config.TerragruntConcurrency,
+
+				// Need for excluding modules from the run
+				config.TerragruntExclude,
+			},
+		)
+		if err != nil {
+			return nil, errors.WithStackTrace(ErrorProcessingModule{UnderlyingError: err, HowThisModuleWasFound: howThisModuleWasFound, ModulePath: terragruntConfigPath})
+		}
+
+		readFilePaths = opts.FileReadTracker.Paths()
+		opts.FileReadTracker = nil
+
+		if cache != nil {
+			cache.Put(terragruntConfigPath, modulePath, terragruntConfig, readFilePaths)
+		}
+	}
+
+	fileDependencies, err := getFileDependenciesForModule(modulePath, terragruntConfig, readFilePaths, terragruntOptions)
 	if err != nil {
-		return nil, errors.WithStackTrace(ErrorProcessingModule{UnderlyingError: err, HowThisModuleWasFound: howThisModuleWasFound, ModulePath: terragruntConfigPath})
+		return nil, err
 	}
 
 	terragruntSource, err := config.GetTerragruntSourceForModule(terragruntOptions.Source, modulePath, terragruntConfig)
@@ -390,7 +498,32 @@ func resolveTerraformModule(terragruntConfigPath string, terragruntOptions *opti
 		opts.OutputPrefix = fmt.Sprintf("[%v] ", modulePath)
 	}
 
-	return &TerraformModule{Path: modulePath, Config: *terragruntConfig, TerragruntOptions: opts}, nil
+	return &TerraformModule{Path: modulePath, Config: *terragruntConfig, TerragruntOptions: opts, FileDependencies: fileDependencies}, nil
+}
+
+// Returns the canonical paths of the file dependencies of the module in the given folder: the given files read while
+// parsing its config and the folder of its terraform source, if it is local.
+func getFileDependenciesForModule(modulePath string, terragruntConfig *config.TerragruntConfig, readFilePaths []string, terragruntOptions *options.TerragruntOptions) ([]string, error) {
+	fileDependencies := []string{}
+	for _, readFilePath := range readFilePaths {
+		canonicalPath, err := util.CanonicalPath(readFilePath, modulePath)
+		if err != nil {
+			return nil, err
+		}
+		fileDependencies = append(fileDependencies, canonicalPath)
+	}
+
+	if terragruntConfig.Terraform != nil && terragruntConfig.Terraform.Source != nil && *terragruntConfig.Terraform.Source != "" {
+		sourcePath, isLocal, err := terraform.LocalSourcePath(*terragruntConfig.Terraform.Source, modulePath)
+		if err != nil {
+			// An invalid source fails when the module runs, so it is only left out of the file dependencies here
+			terragruntOptions.Logger.Debugf("Could not check whether the terraform source of module %s is local: %v", modulePath, err)
+		} else if isLocal && !util.ListContainsElement(fileDependencies, sourcePath) {
+			fileDependencies = append(fileDependencies, sourcePath)
+		}
+	}
+
+	return fileDependencies, nil
 }
 
 // Look through the dependencies of the modules in the given map and resolve the "external" dependency paths listed in
@@ -430,6 +563,7 @@ func resolveExternalDependenciesForModules(moduleMap map[string]*TerraformModule
 			}
 
 			externalDependency.AssumeAlreadyApplied = !shouldApply
+			externalDependency.IsExternal = true
 			allExternalDependencies[externalDependency.Path] = externalDependency
 		}
 	}
@@ -527,6 +661,12 @@ func crosslinkDependencies(moduleMap map[string]*TerraformModule, canonicalTerra
 			return modules, err
 		}
 
+		for _, dependency := range getFileDependencyModules(module, moduleMap) {
+			if !containsModule(dependencies, dependency) {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+
 		module.Dependencies = dependencies
 		modules = append(modules, module)
 	}
@@ -563,6 +703,59 @@ func getDependenciesForModule(module *TerraformModule, moduleMap map[string]*Ter
 	return dependencies, nil
 }
 
+// Returns the modules, other than the given module and the modules in whose folder it is nested, whose folder contains
+// one of the file dependencies of the given module. For each file dependency, the innermost such module is returned.
+func getFileDependencyModules(module *TerraformModule, moduleMap map[string]*TerraformModule) []*TerraformModule {
+	if len(module.FileDependencies) == 0 {
+		return nil
+	}
+
+	otherModules := []*TerraformModule{}
+	for _, key := range getSortedKeys(moduleMap) {
+		otherModule := moduleMap[key]
+		if otherModule.Path != module.Path && !isFileInDir(module.Path, otherModule.Path) {
+			otherModules = append(otherModules, otherModule)
+		}
+	}
+
+	dependencies := []*TerraformModule{}
+	for _, fileDependency := range module.FileDependencies {
+		dependency := findInnermostModuleContainingFile(otherModules, fileDependency)
+		if dependency != nil && !containsModule(dependencies, dependency) {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	return dependencies
+}
+
+// Returns true if the module depends on the given module only because one of its file dependencies is in the folder of
+// that module, rather than through its dependencies block or dependency blocks.
+func (module *TerraformModule) dependsThroughFilesOnly(dependency *TerraformModule) bool {
+	if module.Config.Dependencies != nil {
+		for _, dependencyPath := range module.Config.Dependencies.Paths {
+			if canonicalPath, err := util.CanonicalPath(dependencyPath, module.Path); err == nil && canonicalPath == dependency.Path {
+				return false
+			}
+		}
+	}
+	for _, fileDependency := range module.FileDependencies {
+		if isFileInDir(fileDependency, dependency.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the given list contains a module with the same path as the given module
+func containsModule(modules []*TerraformModule, module *TerraformModule) bool {
+	for _, otherModule := range modules {
+		if otherModule.Path == module.Path {
+			return true
+		}
+	}
+	return false
+}
+
 // Return the keys for the given map in sorted order. This is used to ensure we always iterate over maps of modules
 // in a consistent order (Go does not guarantee iteration order for maps, and usually makes it random)
 func getSortedKeys(modules map[string]*TerraformModule) []string {
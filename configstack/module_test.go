@@ -13,6 +13,7 @@ import (
 	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -113,8 +114,12 @@ func TestResolveTerraformModulesReadConfigFromParentConfig(t *testing.T) {
 		assert.Nil(t, err)
 
 		localsConfigs[name] = map[string]interface{}{
+			"concurrency_group":             "",
+			"concurrency_limits":            interface{}(nil),
 			"dependencies":                  interface{}(nil),
 			"download_dir":                  "",
+			"exclude":                       interface{}(nil),
+			"stack_hook":                    interface{}(nil),
 			"generate":                      map[string]interface{}{},
 			"iam_assume_role_duration":      interface{}(nil),
 			"iam_assume_role_session_name":  "",
@@ -126,6 +131,7 @@ func TestResolveTerraformModulesReadConfigFromParentConfig(t *testing.T) {
 			"retryable_errors":              interface{}(nil),
 			"skip":                          false,
 			"terraform_binary":              "",
+			"terraform_timeout":             interface{}(nil),
 			"terraform_version_constraint":  "",
 			"terragrunt_version_constraint": "",
 		}
@@ -486,6 +492,50 @@ func TestResolveTerraformModulesTwoModulesWithDependenciesExcludedDirsWithNoDepe
 	assertModuleListsEqual(t, expected, actualModules)
 }
 
+func TestFlagExcludedByConfig(t *testing.T) {
+	t.Parallel()
+
+	newModules := func(exclude *config.ExcludeConfig) map[string]*TerraformModule {
+		vpc := &TerraformModule{Path: "/stack/vpc"}
+		db := &TerraformModule{Path: "/stack/db", Dependencies: []*TerraformModule{vpc}}
+		app := &TerraformModule{Path: "/stack/app", Dependencies: []*TerraformModule{db}, Config: config.TerragruntConfig{Exclude: exclude}}
+		other := &TerraformModule{Path: "/stack/other"}
+		return map[string]*TerraformModule{"vpc": vpc, "db": db, "app": app, "other": other}
+	}
+
+	testCases := []struct {
+		name             string
+		exclude          *config.ExcludeConfig
+		command          string
+		expectedExcluded []string
+	}{
+		{"no-exclude-block", nil, "apply", []string{}},
+		{"condition-false", &config.ExcludeConfig{If: false}, "apply", []string{}},
+		{"other-action", &config.ExcludeConfig{If: true, Actions: []string{"destroy"}}, "apply", []string{}},
+		{"matching-action", &config.ExcludeConfig{If: true, Actions: []string{"destroy"}}, "destroy", []string{"app"}},
+		{"all-actions", &config.ExcludeConfig{If: true, Actions: []string{"all"}}, "plan", []string{"app"}},
+		{"exclude-dependencies", &config.ExcludeConfig{If: true, ExcludeDependencies: true}, "apply", []string{"app", "db", "vpc"}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := options.NewTerragruntOptionsForTest("/stack")
+			require.NoError(t, err)
+			opts.TerraformCommand = testCase.command
+
+			modules := newModules(testCase.exclude)
+			flagExcludedByConfig([]*TerraformModule{modules["vpc"], modules["db"], modules["app"], modules["other"]}, opts)
+
+			for name, module := range modules {
+				assert.Equal(t, util.ListContainsElement(testCase.expectedExcluded, name), module.FlagExcluded, name)
+			}
+		})
+	}
+}
+
 func TestResolveTerraformModulesTwoModulesWithDependenciesIncludedDirsWithDependency(t *testing.T) {
 	t.Parallel()
 
@@ -907,3 +957,54 @@ func TestLogReductionHook(t *testing.T) {
 	assert.Contains(t, secondLogEntry, "level=error")
 
 }
+
+func TestCrosslinkDependenciesThroughFileDependencies(t *testing.T) {
+	t.Parallel()
+
+	// /stack is a module of its own, which all the others are nested in
+	root := &TerraformModule{Path: "/stack"}
+	vpc := &TerraformModule{Path: "/stack/vpc"}
+	network := &TerraformModule{Path: "/stack/vpc/network"}
+	app := &TerraformModule{
+		Path:   "/stack/app",
+		Config: config.TerragruntConfig{Dependencies: &config.ModuleDependencies{Paths: []string{"../vpc"}}},
+		FileDependencies: []string{
+			"/stack/vpc/outputs.hcl",
+			"/stack/vpc/network/env.hcl",
+			"/stack/common/env.hcl",
+		},
+	}
+	worker := &TerraformModule{Path: "/stack/app/worker", FileDependencies: []string{"/stack/app/worker.hcl"}}
+
+	moduleMap := map[string]*TerraformModule{}
+	for _, module := range []*TerraformModule{root, vpc, network, app, worker} {
+		moduleMap[module.Path] = module
+	}
+
+	_, err := crosslinkDependencies(moduleMap, []string{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*TerraformModule{vpc, network}, app.Dependencies)
+	assert.False(t, app.dependsThroughFilesOnly(vpc))
+	assert.True(t, app.dependsThroughFilesOnly(network))
+	assert.Empty(t, worker.Dependencies)
+	assert.Empty(t, root.Dependencies)
+}
+
+func TestFlagModulesThatDontIncludeFileDependencies(t *testing.T) {
+	t.Parallel()
+
+	vpc := &TerraformModule{Path: "/stack/vpc", FileDependencies: []string{"/modules/vpc"}}
+	db := &TerraformModule{Path: "/stack/db", FileDependencies: []string{"/stack/common/secrets.yaml"}}
+	app := &TerraformModule{Path: "/stack/app"}
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("/stack/terragrunt.hcl")
+	require.NoError(t, err)
+	terragruntOptions.ModulesThatInclude = []string{"/stack/common/secrets.yaml", "/modules/vpc/main.tf"}
+
+	modules, err := flagModulesThatDontInclude([]*TerraformModule{vpc, db, app}, terragruntOptions)
+	require.NoError(t, err)
+	assert.False(t, modules[0].FlagExcluded)
+	assert.False(t, modules[1].FlagExcluded)
+	assert.True(t, modules[2].FlagExcluded)
+}
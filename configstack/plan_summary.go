@@ -0,0 +1,142 @@
+package configstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+)
+
+// PlanSummary is the number of resources that the plan of a single module adds, changes, destroys and replaces.
+type PlanSummary struct {
+	Add     int `json:"add"`
+	Change  int `json:"change"`
+	Destroy int `json:"destroy"`
+	Replace int `json:"replace"`
+}
+
+// HasChanges returns true if the plan changes at least one resource.
+func (summary *PlanSummary) HasChanges() bool {
+	return summary.Add+summary.Change+summary.Destroy+summary.Replace > 0
+}
+
+// The parts of the JSON representation of a plan, as printed by `terraform show -json`, needed for the summary.
+type planJSON struct {
+	ResourceChanges []struct {
+		Change struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// parsePlanSummary counts the resource changes in the given JSON representation of a plan.
+func parsePlanSummary(data []byte) (*PlanSummary, error) {
+	var plan planJSON
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	summary := &PlanSummary{}
+	for _, resourceChange := range plan.ResourceChanges {
+		actions := resourceChange.Change.Actions
+		switch {
+		case len(actions) == 2:
+			// Replacements are the only changes with two actions: ["delete", "create"] or ["create", "delete"]
+			summary.Replace++
+		case len(actions) == 1 && actions[0] == "create":
+			summary.Add++
+		case len(actions) == 1 && actions[0] == "update":
+			summary.Change++
+		case len(actions) == 1 && actions[0] == "delete":
+			summary.Destroy++
+		}
+	}
+	return summary, nil
+}
+
+// preparePlanSummary makes every module of the stack save the JSON representation of its plan in a new temporary
+// folder, and returns the path of the folder.
+func (stack *Stack) preparePlanSummary() (string, error) {
+	dir, err := os.MkdirTemp("", "terragrunt-plan-summary")
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+
+	for n, module := range stack.Modules {
+		module.TerragruntOptions.PlanJSONFile = filepath.Join(dir, fmt.Sprintf("module-%d.json", n))
+	}
+	return dir, nil
+}
+
+// readPlanSummaries returns the plan summary of each module that planned successfully, keyed by module path.
+func (stack *Stack) readPlanSummaries(terragruntOptions *options.TerragruntOptions, runningModules map[string]*runningModule) map[string]*PlanSummary {
+	summaries := map[string]*PlanSummary{}
+
+	for _, module := range runningModules {
+		if module.Err != nil || module.RunElsewhere || module.Module.AssumeAlreadyApplied {
+			continue
+		}
+
+		data, err := os.ReadFile(module.Module.TerragruntOptions.PlanJSONFile)
+		if err != nil {
+			terragruntOptions.Logger.Warnf("No saved plan found for module %s: %v", module.Module.Path, err)
+			continue
+		}
+
+		summary, err := parsePlanSummary(data)
+		if err != nil {
+			terragruntOptions.Logger.Warnf("Failed to parse the plan of module %s: %v", module.Module.Path, err)
+			continue
+		}
+		summaries[module.Module.Path] = summary
+	}
+
+	return summaries
+}
+
+// writePlanSummaryTable writes a table with the resource changes planned in each module that was run, and their
+// total, to the given writer. Modules without a plan are listed with their run status instead.
+func (stack *Stack) writePlanSummaryTable(w io.Writer, runningModules map[string]*runningModule, summaries map[string]*PlanSummary) error {
+	paths := []string{}
+	for path := range runningModules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "MODULE\tADD\tCHANGE\tDESTROY\tREPLACE\t")
+
+	total := PlanSummary{}
+	for _, path := range paths {
+		displayPath, err := filepath.Rel(stack.Path, path)
+		if err != nil {
+			displayPath = path
+		}
+
+		summary, hasSummary := summaries[path]
+		if !hasSummary {
+			fmt.Fprintf(table, "%s\t-\t-\t-\t-\t%s\n", displayPath, newModuleReport(runningModules[path]).Status)
+			continue
+		}
+
+		note := ""
+		if !summary.HasChanges() {
+			note = "no changes"
+		}
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%d\t%s\n", displayPath, summary.Add, summary.Change, summary.Destroy, summary.Replace, note)
+
+		total.Add += summary.Add
+		total.Change += summary.Change
+		total.Destroy += summary.Destroy
+		total.Replace += summary.Replace
+	}
+	fmt.Fprintf(table, "TOTAL\t%d\t%d\t%d\t%d\t\n", total.Add, total.Change, total.Destroy, total.Replace)
+
+	return errors.WithStackTrace(table.Flush())
+}
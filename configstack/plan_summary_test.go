@@ -0,0 +1,107 @@
+package configstack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlanSummary(t *testing.T) {
+	t.Parallel()
+
+	planJSON := `{
+  "format_version": "1.1",
+  "resource_changes": [
+    {"address": "aws_vpc.main", "change": {"actions": ["create"]}},
+    {"address": "aws_subnet.a", "change": {"actions": ["create"]}},
+    {"address": "aws_subnet.b", "change": {"actions": ["update"]}},
+    {"address": "aws_route.a", "change": {"actions": ["delete"]}},
+    {"address": "aws_instance.a", "change": {"actions": ["delete", "create"]}},
+    {"address": "aws_instance.b", "change": {"actions": ["create", "delete"]}},
+    {"address": "aws_eip.a", "change": {"actions": ["no-op"]}},
+    {"address": "data.aws_ami.a", "change": {"actions": ["read"]}}
+  ]
+}`
+
+	summary, err := parsePlanSummary([]byte(planJSON))
+	require.NoError(t, err)
+	assert.Equal(t, &PlanSummary{Add: 2, Change: 1, Destroy: 1, Replace: 2}, summary)
+	assert.True(t, summary.HasChanges())
+
+	summary, err = parsePlanSummary([]byte(`{"format_version": "1.1"}`))
+	require.NoError(t, err)
+	assert.False(t, summary.HasChanges())
+
+	_, err = parsePlanSummary([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestStackPlanSummary(t *testing.T) {
+	t.Parallel()
+
+	aRan := false
+	moduleA := &TerraformModule{
+		Path:              "/stack/a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "/stack/a", nil, &aRan),
+	}
+
+	bRan := false
+	moduleB := &TerraformModule{
+		Path:              "/stack/b",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "/stack/b", nil, &bRan),
+	}
+
+	errC := errors.New("plan failed in c")
+	cRan := false
+	moduleC := &TerraformModule{
+		Path:              "/stack/c",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "/stack/c", errC, &cRan),
+	}
+
+	stack := &Stack{Path: "/stack", Modules: []*TerraformModule{moduleA, moduleB, moduleC}}
+	dir, err := stack.preparePlanSummary()
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, module := range stack.Modules {
+		assert.Equal(t, dir, filepath.Dir(module.TerragruntOptions.PlanJSONFile))
+	}
+	require.NoError(t, os.WriteFile(moduleA.TerragruntOptions.PlanJSONFile, []byte(`{"resource_changes": [{"change": {"actions": ["create"]}}, {"change": {"actions": ["delete"]}}]}`), 0644))
+	require.NoError(t, os.WriteFile(moduleB.TerragruntOptions.PlanJSONFile, []byte(`{"resource_changes": []}`), 0644))
+
+	runningModules, err := toRunningModules(stack.Modules, NormalOrder)
+	require.NoError(t, err)
+	assert.Error(t, runModules(context.Background(), runningModules, options.DefaultParallelism))
+
+	summaries := stack.readPlanSummaries(moduleA.TerragruntOptions, runningModules)
+	assert.Equal(t, map[string]*PlanSummary{
+		"/stack/a": {Add: 1, Destroy: 1},
+		"/stack/b": {},
+	}, summaries)
+
+	var out bytes.Buffer
+	require.NoError(t, stack.writePlanSummaryTable(&out, runningModules, summaries))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, []string{"MODULE", "ADD", "CHANGE", "DESTROY", "REPLACE"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"a", "1", "0", "1", "0"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"b", "0", "0", "0", "0", "no", "changes"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"c", "-", "-", "-", "-", "failed"}, strings.Fields(lines[3]))
+	assert.Equal(t, []string{"TOTAL", "1", "0", "1", "0"}, strings.Fields(lines[4]))
+}
@@ -0,0 +1,335 @@
+package configstack
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+const (
+	// ReportFormatJSON renders the run report as a JSON document.
+	ReportFormatJSON = "json"
+
+	// ReportFormatJUnit renders the run report as JUnit XML, which most CI systems know how to display.
+	ReportFormatJUnit = "junit"
+
+	// The number of lines from the end of stderr of a failed module to keep in the report.
+	reportStderrTailLines = 20
+)
+
+// ModuleRunStatus is the final state of a single module after a run-all command has finished.
+type ModuleRunStatus string
+
+const (
+	ModuleSucceeded               ModuleRunStatus = "succeeded"
+	ModuleFailed                  ModuleRunStatus = "failed"
+	ModuleSkippedDependencyFailed ModuleRunStatus = "skipped"
+	ModuleExcluded                ModuleRunStatus = "excluded"
+	ModuleAssumeAlreadyApplied    ModuleRunStatus = "assume-already-applied"
+	ModuleCancelledStatus         ModuleRunStatus = "cancelled"
+	ModuleTimedOutStatus          ModuleRunStatus = "timed-out"
+	ModuleOtherShard              ModuleRunStatus = "other-shard"
+	ModuleNotApprovedStatus       ModuleRunStatus = "not-approved"
+)
+
+// ModuleReport is the outcome of running the terraform command in a single module of the stack.
+type ModuleReport struct {
+	Path            string          `json:"path"`
+	Status          ModuleRunStatus `json:"status"`
+	StartTime       *time.Time      `json:"start_time,omitempty"`
+	EndTime         *time.Time      `json:"end_time,omitempty"`
+	DurationSeconds float64         `json:"duration_seconds"`
+	ExitCode        *int            `json:"exit_code,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	StderrTail      string          `json:"stderr_tail,omitempty"`
+	PlanSummary     *PlanSummary    `json:"plan_summary,omitempty"`
+	Drift           *ModuleDrift    `json:"drift,omitempty"`
+	Attempts        int             `json:"attempts,omitempty"`
+}
+
+// RunReport is a machine-readable summary of a run-all command, listing the outcome of every module in the stack.
+type RunReport struct {
+	Command         string          `json:"command"`
+	StartTime       time.Time       `json:"start_time"`
+	EndTime         time.Time       `json:"end_time"`
+	DurationSeconds float64         `json:"duration_seconds"`
+	Modules         []*ModuleReport `json:"modules"`
+}
+
+// newRunReport assembles a RunReport from the modules of the stack and the runningModules that tracked their
+// execution. Modules that are in the stack but not in runningModules were excluded from the run.
+func newRunReport(command string, startTime time.Time, endTime time.Time, modules []*TerraformModule, runningModules map[string]*runningModule) *RunReport {
+	report := &RunReport{
+		Command:         command,
+		StartTime:       startTime,
+		EndTime:         endTime,
+		DurationSeconds: endTime.Sub(startTime).Seconds(),
+		Modules:         []*ModuleReport{},
+	}
+
+	for _, module := range modules {
+		runningModule, wasRun := runningModules[module.Path]
+		if !wasRun {
+			report.Modules = append(report.Modules, &ModuleReport{Path: module.Path, Status: ModuleExcluded})
+			continue
+		}
+		report.Modules = append(report.Modules, newModuleReport(runningModule))
+	}
+
+	sort.Slice(report.Modules, func(i, j int) bool {
+		return report.Modules[i].Path < report.Modules[j].Path
+	})
+
+	return report
+}
+
+// setPlanSummaries adds the given plan summaries, keyed by module path, to the reports of the modules.
+func (report *RunReport) setPlanSummaries(summaries map[string]*PlanSummary) {
+	for _, module := range report.Modules {
+		module.PlanSummary = summaries[module.Path]
+	}
+}
+
+// setDrifts adds the given drift results, keyed by module path, to the reports of the modules.
+func (report *RunReport) setDrifts(drifts map[string]*ModuleDrift) {
+	for _, module := range report.Modules {
+		module.Drift = drifts[module.Path]
+	}
+}
+
+// newModuleReport converts the state tracked in the given runningModule to a ModuleReport.
+func newModuleReport(module *runningModule) *ModuleReport {
+	report := &ModuleReport{Path: module.Module.Path, Attempts: module.Attempts}
+
+	if !module.StartTime.IsZero() {
+		startTime := module.StartTime
+		report.StartTime = &startTime
+	}
+	if !module.EndTime.IsZero() {
+		endTime := module.EndTime
+		report.EndTime = &endTime
+	}
+	if report.StartTime != nil && report.EndTime != nil {
+		report.DurationSeconds = report.EndTime.Sub(*report.StartTime).Seconds()
+	}
+
+	if module.RunElsewhere {
+		report.Status = ModuleOtherShard
+		return report
+	}
+
+	if module.Err == nil {
+		if module.Module.AssumeAlreadyApplied {
+			report.Status = ModuleAssumeAlreadyApplied
+		} else {
+			report.Status = ModuleSucceeded
+			exitCode := 0
+			if module.PlanHasChanges {
+				exitCode = shell.DetailedExitCodeChanges
+			}
+			report.ExitCode = &exitCode
+		}
+		return report
+	}
+
+	report.Error = module.Err.Error()
+
+	var dependencyErr DependencyFinishedWithError
+	if goerrors.As(module.Err, &dependencyErr) {
+		report.Status = ModuleSkippedDependencyFailed
+		return report
+	}
+
+	var cancelledErr ModuleCancelled
+	if goerrors.As(module.Err, &cancelledErr) {
+		report.Status = ModuleCancelledStatus
+		return report
+	}
+
+	var notApprovedErr ModuleNotApproved
+	if goerrors.As(module.Err, &notApprovedErr) {
+		report.Status = ModuleNotApprovedStatus
+		return report
+	}
+
+	report.Status = ModuleFailed
+	var timedOutErr ModuleTimedOut
+	if goerrors.As(module.Err, &timedOutErr) {
+		report.Status = ModuleTimedOutStatus
+	}
+
+	if exitCode, err := shell.GetExitCode(module.Err); err == nil {
+		report.ExitCode = &exitCode
+	}
+
+	var processErr shell.ProcessExecutionError
+	if goerrors.As(module.Err, &processErr) {
+		report.StderrTail = tailLines(processErr.Stderr, reportStderrTailLines)
+	}
+
+	return report
+}
+
+// Return the last n lines of the given text.
+func tailLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// WriteToFile writes the report to the given path in the given format. If format is empty, it is inferred from the
+// file extension: `.xml` files get JUnit XML and everything else gets JSON.
+func (report *RunReport) WriteToFile(path string, format string) error {
+	if format == "" {
+		format = ReportFormatJSON
+		if strings.EqualFold(filepath.Ext(path), ".xml") {
+			format = ReportFormatJUnit
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer file.Close()
+
+	switch format {
+	case ReportFormatJSON:
+		return report.WriteJSON(file)
+	case ReportFormatJUnit:
+		return report.WriteJUnit(file)
+	default:
+		return errors.WithStackTrace(UnsupportedReportFormat(format))
+	}
+}
+
+// WriteJSON writes the report to the given writer as an indented JSON document.
+func (report *RunReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.WithStackTrace(encoder.Encode(report))
+}
+
+// ReadRunReport reads a run report that was written as JSON, such as with --terragrunt-report-file.
+func ReadRunReport(path string) (*RunReport, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	var report RunReport
+	if err := json.Unmarshal(contents, &report); err != nil {
+		return nil, errors.WithStackTrace(InvalidRunReport{Path: path, Err: err})
+	}
+	return &report, nil
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report to the given writer as JUnit XML, with one test case per module.
+func (report *RunReport) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name:      fmt.Sprintf("terragrunt run-all %s", report.Command),
+		Time:      fmt.Sprintf("%.3f", report.DurationSeconds),
+		Timestamp: report.StartTime.Format(time.RFC3339),
+	}
+
+	for _, module := range report.Modules {
+		testCase := junitTestCase{
+			Name:      module.Path,
+			ClassName: "terragrunt." + report.Command,
+			Time:      fmt.Sprintf("%.3f", module.DurationSeconds),
+			SystemErr: module.StderrTail,
+		}
+
+		switch module.Status {
+		case ModuleFailed, ModuleTimedOutStatus:
+			testCase.Failure = &junitMessage{Message: string(module.Status), Body: module.Error}
+			suite.Failures++
+		case ModuleSkippedDependencyFailed, ModuleCancelledStatus, ModuleNotApprovedStatus, ModuleExcluded, ModuleAssumeAlreadyApplied, ModuleOtherShard:
+			testCase.Skipped = &junitMessage{Message: string(module.Status), Body: module.Error}
+			suite.Skipped++
+		case ModuleSucceeded:
+			if module.Drift != nil && module.Drift.Drifted {
+				testCase.Failure = &junitMessage{Message: "drifted", Body: strings.Join(module.Drift.Resources, "\n")}
+				suite.Failures++
+			}
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+		suite.Tests++
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return errors.WithStackTrace(err)
+}
+
+// Custom error types
+
+type UnsupportedReportFormat string
+
+func (format UnsupportedReportFormat) Error() string {
+	return fmt.Sprintf("Unsupported run report format %q. Supported formats are: %s, %s", string(format), ReportFormatJSON, ReportFormatJUnit)
+}
+
+type InvalidRunReport struct {
+	Path string
+	Err  error
+}
+
+func (err InvalidRunReport) Error() string {
+	return fmt.Sprintf("Could not parse the run report %s. Only run reports written as JSON are supported: %v", err.Path, err.Err)
+}
@@ -0,0 +1,249 @@
+package configstack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunReportModuleStatuses(t *testing.T) {
+	t.Parallel()
+
+	exitErr := exec.Command("sh", "-c", "exit 3").Run()
+	require.Error(t, exitErr)
+
+	processErr := shell.ProcessExecutionError{
+		Err:        exitErr,
+		Stderr:     "line 1\nline 2\nError: something broke\n",
+		WorkingDir: "b",
+	}
+
+	aRan := false
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "a", nil, &aRan),
+	}
+
+	bRan := false
+	moduleB := &TerraformModule{
+		Path:              "b",
+		Dependencies:      []*TerraformModule{moduleA},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "b", processErr, &bRan),
+	}
+
+	cRan := false
+	moduleC := &TerraformModule{
+		Path:              "c",
+		Dependencies:      []*TerraformModule{moduleB},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "c", nil, &cRan),
+	}
+
+	dRan := false
+	moduleD := &TerraformModule{
+		Path:                 "d",
+		Dependencies:         []*TerraformModule{},
+		Config:               config.TerragruntConfig{},
+		TerragruntOptions:    optionsWithMockTerragruntCommand(t, "d", nil, &dRan),
+		AssumeAlreadyApplied: true,
+	}
+
+	eRan := false
+	moduleE := &TerraformModule{
+		Path:              "e",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "e", nil, &eRan),
+		FlagExcluded:      true,
+	}
+
+	modules := []*TerraformModule{moduleA, moduleB, moduleC, moduleD, moduleE}
+	runningModules, err := toRunningModules(modules, NormalOrder)
+	require.NoError(t, err)
+
+	startTime := time.Now()
+	err = runModules(context.Background(), runningModules, options.DefaultParallelism)
+	assert.Error(t, err)
+
+	report := newRunReport("apply", startTime, time.Now(), modules, runningModules)
+	require.Len(t, report.Modules, 5)
+
+	statuses := map[string]ModuleRunStatus{}
+	for _, module := range report.Modules {
+		statuses[module.Path] = module.Status
+	}
+	assert.Equal(t, map[string]ModuleRunStatus{
+		"a": ModuleSucceeded,
+		"b": ModuleFailed,
+		"c": ModuleSkippedDependencyFailed,
+		"d": ModuleAssumeAlreadyApplied,
+		"e": ModuleExcluded,
+	}, statuses)
+
+	failed := report.Modules[1]
+	require.NotNil(t, failed.ExitCode)
+	assert.Equal(t, 3, *failed.ExitCode)
+	assert.Equal(t, "line 1\nline 2\nError: something broke", failed.StderrTail)
+	assert.NotNil(t, failed.StartTime)
+	assert.NotNil(t, failed.EndTime)
+	assert.NotEmpty(t, failed.Error)
+}
+
+func TestRunReportModuleTimedOut(t *testing.T) {
+	t.Parallel()
+
+	timedOutErr := ModuleTimedOut{
+		Path:    "a",
+		Timeout: time.Minute,
+		Err:     shell.ProcessExecutionError{Err: errors.New("signal: interrupt"), Stderr: "Interrupt received.\n", WorkingDir: "a"},
+	}
+
+	aRan := false
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "a", timedOutErr, &aRan),
+	}
+
+	modules := []*TerraformModule{moduleA}
+	runningModules, err := toRunningModules(modules, NormalOrder)
+	require.NoError(t, err)
+	assert.Error(t, runModules(context.Background(), runningModules, options.DefaultParallelism))
+
+	report := newRunReport("apply", time.Now(), time.Now(), modules, runningModules)
+	require.Len(t, report.Modules, 1)
+	assert.Equal(t, ModuleTimedOutStatus, report.Modules[0].Status)
+	assert.Equal(t, "Interrupt received.", report.Modules[0].StderrTail)
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteJUnit(&out))
+
+	var actual junitTestSuites
+	require.NoError(t, xml.Unmarshal(out.Bytes(), &actual))
+	assert.Equal(t, 1, actual.Suites[0].Failures)
+}
+
+func TestRunReportWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	exitCode := 0
+	report := &RunReport{
+		Command: "plan",
+		Modules: []*ModuleReport{
+			{Path: "a", Status: ModuleSucceeded, ExitCode: &exitCode},
+			{Path: "b", Status: ModuleExcluded},
+		},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteJSON(&out))
+
+	var actual RunReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &actual))
+	assert.Equal(t, "plan", actual.Command)
+	require.Len(t, actual.Modules, 2)
+	assert.Equal(t, ModuleSucceeded, actual.Modules[0].Status)
+	assert.Equal(t, 0, *actual.Modules[0].ExitCode)
+	assert.Nil(t, actual.Modules[1].ExitCode)
+}
+
+func TestRunReportWriteJUnit(t *testing.T) {
+	t.Parallel()
+
+	report := &RunReport{
+		Command: "apply",
+		Modules: []*ModuleReport{
+			{Path: "a", Status: ModuleSucceeded},
+			{Path: "b", Status: ModuleFailed, Error: "boom", StderrTail: "Error: boom"},
+			{Path: "c", Status: ModuleSkippedDependencyFailed},
+		},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteJUnit(&out))
+
+	var actual junitTestSuites
+	require.NoError(t, xml.Unmarshal(out.Bytes(), &actual))
+	require.Len(t, actual.Suites, 1)
+
+	suite := actual.Suites[0]
+	assert.Equal(t, 3, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Equal(t, 1, suite.Skipped)
+	require.NotNil(t, suite.TestCases[1].Failure)
+	assert.Equal(t, "boom", suite.TestCases[1].Failure.Body)
+	assert.Equal(t, "Error: boom", suite.TestCases[1].SystemErr)
+}
+
+func TestRunReportWriteToFileInfersFormat(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	report := &RunReport{Command: "plan", Modules: []*ModuleReport{{Path: "a", Status: ModuleSucceeded}}}
+
+	testCases := []struct {
+		fileName       string
+		format         string
+		expectedPrefix string
+	}{
+		{"report.json", "", "{"},
+		{"report.xml", "", xml.Header},
+		{"report.txt", ReportFormatJUnit, xml.Header},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(fmt.Sprintf("%s-%s", testCase.fileName, testCase.format), func(t *testing.T) {
+			path := filepath.Join(tmpDir, testCase.fileName)
+			require.NoError(t, report.WriteToFile(path, testCase.format))
+
+			contents, err := util.ReadFileAsString(path)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(contents, testCase.expectedPrefix))
+		})
+	}
+
+	err := report.WriteToFile(filepath.Join(tmpDir, "report.out"), "yaml")
+	assert.Error(t, err)
+}
+
+func TestReadRunReport(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	report := &RunReport{Command: "apply", Modules: []*ModuleReport{{Path: "/stack/a", Status: ModuleFailed, Error: "boom"}}}
+
+	jsonPath := filepath.Join(tmpDir, "report.json")
+	require.NoError(t, report.WriteToFile(jsonPath, ""))
+
+	actual, err := ReadRunReport(jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, "apply", actual.Command)
+	assert.Equal(t, report.Modules, actual.Modules)
+
+	junitPath := filepath.Join(tmpDir, "report.xml")
+	require.NoError(t, report.WriteToFile(junitPath, ""))
+
+	_, err = ReadRunReport(junitPath)
+	var invalidReportErr InvalidRunReport
+	assert.True(t, errors.As(err, &invalidReportErr))
+}
@@ -0,0 +1,110 @@
+package configstack
+
+import (
+	"context"
+	goerrors "errors"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// retryFailedModules runs the modules of a finished run-all that failed again, in rounds, until they all succeed or each
+// of them ran RunAllRetryMaxAttempts times. Each round only runs the failed modules and the modules that were skipped
+// because of them, in dependency order, so that a module whose failure clears once a dependency is applied again gets
+// the chance to. The rounds are separated by an exponential backoff that starts at RetrySleepIntervalSec. The modules of
+// each round replace their previous run in runningModules. The modules run with the given context, and no more rounds
+// are run once it is done. Returns the errors of the modules that still failed.
+func retryFailedModules(ctx context.Context, terragruntOptions *options.TerragruntOptions, runningModules map[string]*runningModule, dependencyOrder DependencyOrder, shard *runShard, runErr error) error {
+	sleepInterval := terragruntOptions.RetrySleepIntervalSec
+
+	for attempt := 2; runErr != nil && attempt <= terragruntOptions.RunAllRetryMaxAttempts; attempt++ {
+		retryModules := toRetryRunningModules(runningModules, dependencyOrder)
+		if len(retryModules) == 0 {
+			break
+		}
+		if shard != nil {
+			shard.attach(retryModules)
+		}
+
+		terragruntOptions.Logger.Infof("%d module(s) failed or were skipped because a dependency failed. Sleeping %v before running them again (attempt %d of %d).", len(retryModules), sleepInterval, attempt, terragruntOptions.RunAllRetryMaxAttempts)
+		select {
+		case <-ctx.Done():
+			terragruntOptions.Logger.Infof("Run-all cancelled. Not running the %d module(s) again.", len(retryModules))
+			return runErr
+		case <-time.After(sleepInterval):
+		}
+		sleepInterval *= 2
+
+		// runModules only fails without a module error if the modules could not be run at all
+		if err := runModules(ctx, retryModules, terragruntOptions.Parallelism); err != nil && collectErrors(retryModules) == nil {
+			return err
+		}
+		for path, module := range retryModules {
+			runningModules[path] = module
+		}
+		runErr = collectErrors(runningModules)
+	}
+
+	return runErr
+}
+
+// Returns new runningModules, cross-linked in the given order, for the modules of the given finished run that should
+// be run again: the modules that failed, except those that were rejected with --terragrunt-run-all-confirm-each or run
+// by another shard, and the modules that were skipped or cancelled, as long as none of the modules they wait for failed
+// without being run again. The number of attempts of each module carries over from the finished run.
+func toRetryRunningModules(finished map[string]*runningModule, dependencyOrder DependencyOrder) map[string]*runningModule {
+	// The paths of the modules each module waits for in the given order
+	waitsFor := map[string][]string{}
+	for path, module := range finished {
+		for _, dependency := range module.Module.Dependencies {
+			if _, isRun := finished[dependency.Path]; !isRun {
+				continue
+			}
+			switch dependencyOrder {
+			case NormalOrder:
+				waitsFor[path] = append(waitsFor[path], dependency.Path)
+			case ReverseOrder:
+				waitsFor[dependency.Path] = append(waitsFor[dependency.Path], path)
+			}
+		}
+	}
+
+	shouldRetry := map[string]bool{}
+	var checkRetry func(path string) bool
+	checkRetry = func(path string) bool {
+		if retry, isChecked := shouldRetry[path]; isChecked {
+			return retry
+		}
+
+		module := finished[path]
+		var notApprovedErr ModuleNotApproved
+		retry := module.Err != nil && !module.RunElsewhere && !goerrors.As(module.Err, &notApprovedErr)
+		for _, dependencyPath := range waitsFor[path] {
+			if retry && finished[dependencyPath].Err != nil && !checkRetry(dependencyPath) {
+				retry = false
+			}
+		}
+
+		shouldRetry[path] = retry
+		return retry
+	}
+
+	modules := map[string]*runningModule{}
+	for path, module := range finished {
+		if checkRetry(path) {
+			modules[path] = newRunningModule(module.Module)
+			modules[path].Attempts = module.Attempts
+		}
+	}
+
+	for path, module := range modules {
+		for _, dependencyPath := range waitsFor[path] {
+			if dependency, isRetried := modules[dependencyPath]; isRetried {
+				module.Dependencies[dependencyPath] = dependency
+				dependency.NotifyWhenDone = append(dependency.NotifyWhenDone, module)
+			}
+		}
+	}
+
+	return modules
+}
@@ -0,0 +1,164 @@
+package configstack
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToRetryRunningModules(t *testing.T) {
+	t.Parallel()
+
+	vpc := &TerraformModule{Path: "/stack/vpc"}
+	app := &TerraformModule{Path: "/stack/app", Dependencies: []*TerraformModule{vpc}}
+	dns := &TerraformModule{Path: "/stack/dns"}
+	web := &TerraformModule{Path: "/stack/web", Dependencies: []*TerraformModule{app, dns}}
+	db := &TerraformModule{Path: "/stack/db"}
+
+	vpcErr := errors.New("vpc failed")
+	finished := map[string]*runningModule{
+		vpc.Path: {Module: vpc, Err: vpcErr, Attempts: 1},
+		app.Path: {Module: app, Err: DependencyFinishedWithError{app, vpc, vpcErr}},
+		dns.Path: {Module: dns, Err: ModuleNotApproved{Path: dns.Path}, Attempts: 1},
+		web.Path: {Module: web, Err: DependencyFinishedWithError{web, app, vpcErr}},
+		db.Path:  {Module: db, Attempts: 1},
+	}
+
+	modules := toRetryRunningModules(finished, NormalOrder)
+	require.Len(t, modules, 2)
+	assert.Equal(t, 1, modules[vpc.Path].Attempts)
+	assert.Empty(t, modules[vpc.Path].Dependencies)
+	assert.Equal(t, []*runningModule{modules[app.Path]}, modules[vpc.Path].NotifyWhenDone)
+	assert.Equal(t, map[string]*runningModule{vpc.Path: modules[vpc.Path]}, modules[app.Path].Dependencies)
+
+	modules = toRetryRunningModules(finished, ReverseOrder)
+	assert.Equal(t, map[string]*runningModule{app.Path: modules[app.Path]}, modules[vpc.Path].Dependencies)
+}
+
+func TestStackRunRetriesFailedSubtree(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	reportFile := filepath.Join(tmpDir, "report.json")
+
+	var mutex sync.Mutex
+	runs := map[string]int{}
+	newModule := func(name string, failures int, dependencies ...*TerraformModule) *TerraformModule {
+		path := filepath.Join(tmpDir, name)
+		opts, err := options.NewTerragruntOptionsForTest(filepath.Join(path, config.DefaultTerragruntConfigPath))
+		require.NoError(t, err)
+		opts.RunTerragrunt = func(_ *options.TerragruntOptions) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			runs[name]++
+			if runs[name] <= failures {
+				return errors.New(name + " failed")
+			}
+			return nil
+		}
+		return &TerraformModule{Path: path, Dependencies: dependencies, TerragruntOptions: opts}
+	}
+
+	vpc := newModule("vpc", 0)
+	iam := newModule("iam", 1, vpc)
+	app := newModule("app", 0, iam)
+	db := newModule("db", 5)
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(tmpDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.TerraformCommand = "plan"
+	opts.TerraformCliArgs = []string{"plan"}
+	opts.RunAllRetryMaxAttempts = 3
+	opts.RetrySleepIntervalSec = time.Millisecond
+	opts.ReportFile = reportFile
+
+	stack := &Stack{Path: tmpDir, Modules: []*TerraformModule{vpc, iam, app, db}}
+	assert.Error(t, stack.Run(opts))
+	assert.Equal(t, map[string]int{"vpc": 1, "iam": 2, "app": 1, "db": 3}, runs)
+
+	report, err := ReadRunReport(reportFile)
+	require.NoError(t, err)
+
+	attempts := map[string]int{}
+	statuses := map[string]ModuleRunStatus{}
+	for _, module := range report.Modules {
+		attempts[filepath.Base(module.Path)] = module.Attempts
+		statuses[filepath.Base(module.Path)] = module.Status
+	}
+	assert.Equal(t, map[string]int{"vpc": 1, "iam": 2, "app": 1, "db": 3}, attempts)
+	assert.Equal(t, map[string]ModuleRunStatus{"vpc": ModuleSucceeded, "iam": ModuleSucceeded, "app": ModuleSucceeded, "db": ModuleFailed}, statuses)
+}
+
+func TestStackRunRetriesFailedDependencyBeforeDependent(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+
+	var mutex sync.Mutex
+	var runs []string
+	newModule := func(name string, failures int, dependencies ...*TerraformModule) *TerraformModule {
+		path := filepath.Join(tmpDir, name)
+		opts, err := options.NewTerragruntOptionsForTest(filepath.Join(path, config.DefaultTerragruntConfigPath))
+		require.NoError(t, err)
+		attempts := 0
+		opts.RunTerragrunt = func(_ *options.TerragruntOptions) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			runs = append(runs, name)
+			attempts++
+			if attempts <= failures {
+				return errors.New(name + " failed")
+			}
+			return nil
+		}
+		return &TerraformModule{Path: path, Dependencies: dependencies, TerragruntOptions: opts}
+	}
+
+	vpc := newModule("vpc", 1)
+	app := newModule("app", 0, vpc)
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(tmpDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.TerraformCommand = "plan"
+	opts.TerraformCliArgs = []string{"plan"}
+	opts.RunAllRetryMaxAttempts = 2
+	opts.RetrySleepIntervalSec = time.Millisecond
+
+	stack := &Stack{Path: tmpDir, Modules: []*TerraformModule{vpc, app}}
+	require.NoError(t, stack.Run(opts))
+	assert.Equal(t, []string{"vpc", "vpc", "app"}, runs)
+}
+
+func TestRetryFailedModulesStopsWhenCancelled(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(tmpDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.RunAllRetryMaxAttempts = 3
+	opts.RetrySleepIntervalSec = time.Hour
+
+	runs := 0
+	opts.RunTerragrunt = func(_ *options.TerragruntOptions) error {
+		runs++
+		return nil
+	}
+
+	vpcErr := errors.New("vpc failed")
+	vpc := &TerraformModule{Path: filepath.Join(tmpDir, "vpc"), TerragruntOptions: opts}
+	runningModules := map[string]*runningModule{vpc.Path: {Module: vpc, Err: vpcErr, Attempts: 1}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = retryFailedModules(ctx, opts, runningModules, NormalOrder, nil, vpcErr)
+	assert.ErrorIs(t, err, vpcErr)
+	assert.Equal(t, 0, runs)
+}
@@ -1,8 +1,12 @@
 package configstack
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/gruntwork-io/terragrunt/pkg/errors"
 	"github.com/gruntwork-io/terragrunt/shell"
@@ -27,6 +31,21 @@ type runningModule struct {
 	Dependencies   map[string]*runningModule
 	NotifyWhenDone []*runningModule
 	FlagExcluded   bool
+	StartTime      time.Time
+	EndTime        time.Time
+
+	// The shard of a distributed run-all this module is part of, or nil if the run-all is not distributed. If
+	// RunElsewhere is set, the module is run by another shard, and finishes once its done marker shows up.
+	Shard        *runShard
+	RunElsewhere bool
+
+	// Set if the module ran plan -detailed-exitcode and the plan has changes, which terraform reports with exit code
+	// 2. The module is considered successful anyway, so that its dependents run too.
+	PlanHasChanges bool
+
+	// The number of times the module has been run, which is more than one if it was run again by
+	// --terragrunt-run-all-retry-max-attempts after it failed.
+	Attempts int
 }
 
 // This controls in what order dependencies should be enforced between modules
@@ -60,7 +79,7 @@ func RunModules(modules []*TerraformModule, parallelism int) error {
 	if err != nil {
 		return err
 	}
-	return runModules(runningModules, parallelism)
+	return runModules(context.Background(), runningModules, parallelism)
 }
 
 // Run the given map of module path to runningModule. To "run" a module, execute the RunTerragrunt command in its
@@ -71,7 +90,7 @@ func RunModulesReverseOrder(modules []*TerraformModule, parallelism int) error {
 	if err != nil {
 		return err
 	}
-	return runModules(runningModules, parallelism)
+	return runModules(context.Background(), runningModules, parallelism)
 }
 
 // Run the given map of module path to runningModule. To "run" a module, execute the RunTerragrunt command in its
@@ -81,7 +100,7 @@ func RunModulesIgnoreOrder(modules []*TerraformModule, parallelism int) error {
 	if err != nil {
 		return err
 	}
-	return runModules(runningModules, parallelism)
+	return runModules(context.Background(), runningModules, parallelism)
 }
 
 // Convert the list of modules to a map from module path to a runningModule struct. This struct contains information
@@ -160,16 +179,26 @@ func removeFlagExcluded(modules map[string]*runningModule) map[string]*runningMo
 
 // Run the given map of module path to runningModule. To "run" a module, execute the RunTerragrunt command in its
 // TerragruntOptions object. The modules will be executed in an order determined by their inter-dependencies, using
-// as much concurrency as possible.
-func runModules(modules map[string]*runningModule, parallelism int) error {
+// as much concurrency as possible, within the parallelism limit and the limits of the concurrency groups of the modules.
+// If a module fails and its TerragruntOptions have FailFast set, the run is cancelled: modules that have not started
+// yet are skipped and the modules that are running get interrupted.
+func runModules(ctx context.Context, modules map[string]*runningModule, parallelism int) error {
 	var waitGroup sync.WaitGroup
 	var semaphore = make(chan struct{}, parallelism) // Make a semaphore from a buffered channel
 
+	groupSemaphores, err := concurrencyGroupSemaphores(modules)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	for _, module := range modules {
 		waitGroup.Add(1)
 		go func(module *runningModule) {
 			defer waitGroup.Done()
-			module.runModuleWhenReady(semaphore)
+			module.runModuleWhenReady(ctx, cancel, semaphore, groupSemaphores[module.Module.Config.ConcurrencyGroup])
 		}(module)
 	}
 
@@ -191,15 +220,95 @@ func collectErrors(modules map[string]*runningModule) error {
 	return result.ErrorOrNil()
 }
 
-// Run a module once all of its dependencies have finished executing.
-func (module *runningModule) runModuleWhenReady(semaphore chan struct{}) {
+// Returns the sorted paths of the given modules whose plan, run with -detailed-exitcode, has changes.
+func modulesWithPlanChanges(modules map[string]*runningModule) []string {
+	paths := []string{}
+	for _, module := range modules {
+		if module.PlanHasChanges {
+			paths = append(paths, module.Module.Path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Returns a semaphore for each concurrency group of the given modules that has a limit. The limit of a group is taken
+// from the --terragrunt-concurrency-limit flag or, if the flag doesn't set it, from the concurrency_limits attribute of
+// the configs. When the configs of the modules set different limits for the same group, the lowest one is used.
+func concurrencyGroupSemaphores(modules map[string]*runningModule) (map[string]chan struct{}, error) {
+	configLimits := map[string]int{}
+	cliLimits := map[string]int{}
+	for _, module := range modules {
+		for group, limit := range module.Module.Config.ConcurrencyLimits {
+			if currentLimit, hasLimit := configLimits[group]; !hasLimit || limit < currentLimit {
+				configLimits[group] = limit
+			}
+		}
+		for group, limit := range module.Module.TerragruntOptions.ConcurrencyLimits {
+			cliLimits[group] = limit
+		}
+	}
+	for group, limit := range cliLimits {
+		configLimits[group] = limit
+	}
+
+	semaphores := map[string]chan struct{}{}
+	for group, limit := range configLimits {
+		if limit < 1 {
+			return nil, errors.WithStackTrace(InvalidConcurrencyLimit{Group: group, Limit: limit})
+		}
+		semaphores[group] = make(chan struct{}, limit)
+	}
+
+	for _, module := range modules {
+		group := module.Module.Config.ConcurrencyGroup
+		if _, hasLimit := semaphores[group]; group != "" && !hasLimit {
+			module.Module.TerragruntOptions.Logger.Debugf("Concurrency group %s of module %s has no limit", group, module.Module.Path)
+		}
+	}
+
+	return semaphores, nil
+}
+
+// Run a module once all of its dependencies have finished executing. The module first takes a slot of its concurrency
+// group, if the group has a limit, and then a slot of the global parallelism limit, so that modules waiting for their
+// group don't hold a global slot. If the module fails in fail-fast mode, the whole run is cancelled through cancelRun.
+func (module *runningModule) runModuleWhenReady(ctx context.Context, cancelRun context.CancelFunc, semaphore chan struct{}, groupSemaphore chan struct{}) {
+	if module.RunElsewhere {
+		module.moduleFinished(module.Shard.waitForModule(ctx, module))
+		return
+	}
+
 	err := module.waitForDependencies()
+	if err == nil && groupSemaphore != nil {
+		groupSemaphore <- struct{}{} // Will block if the limit of the concurrency group is met
+		defer func() {
+			<-groupSemaphore
+		}()
+	}
 	semaphore <- struct{}{} // Add one to the buffered channel. Will block if parallelism limit is met
 	defer func() {
 		<-semaphore // Remove one from the buffered channel
 	}()
 	if err == nil {
-		err = module.runNow()
+		if ctx.Err() != nil {
+			module.Module.TerragruntOptions.Logger.Debugf("Run was cancelled before module %s started", module.Module.Path)
+			err = ModuleCancelled{module.Module}
+		} else {
+			err = module.runNow(ctx)
+			if err != nil && module.Module.TerragruntOptions.FailFast && ctx.Err() == nil {
+				module.Module.TerragruntOptions.Logger.Errorf("Module %s failed and --terragrunt-fail-fast is set. Cancelling all remaining modules.", module.Module.Path)
+				cancelRun()
+			}
+		}
+	}
+	if module.Shard != nil {
+		if markerErr := module.Shard.markDone(module.Module, err); markerErr != nil {
+			module.Module.TerragruntOptions.Logger.Errorf("Failed to record that module %s finished for the other shards: %v", module.Module.Path, markerErr)
+			if err == nil {
+				err = markerErr
+			}
+		}
 	}
 	module.moduleFinished(err)
 }
@@ -227,16 +336,26 @@ func (module *runningModule) waitForDependencies() error {
 	return nil
 }
 
-// Run a module right now by executing the RunTerragrunt command of its TerragruntOptions field.
-func (module *runningModule) runNow() error {
+// Run a module right now by executing the RunTerragrunt command of its TerragruntOptions field. The given context is
+// attached to the TerragruntOptions, so that the commands run for this module are interrupted when it is cancelled.
+func (module *runningModule) runNow(ctx context.Context) error {
 	module.Status = Running
+	module.StartTime = time.Now()
+	module.Attempts++
 
 	if module.Module.AssumeAlreadyApplied {
 		module.Module.TerragruntOptions.Logger.Debugf("Assuming module %s has already been applied and skipping it", module.Module.Path)
 		return nil
 	} else {
 		module.Module.TerragruntOptions.Logger.Debugf("Running module %s now", module.Module.Path)
-		return module.Module.TerragruntOptions.RunTerragrunt(module.Module.TerragruntOptions)
+		module.Module.TerragruntOptions.Context = ctx
+		err := module.Module.TerragruntOptions.RunTerragrunt(module.Module.TerragruntOptions)
+		if err != nil && shell.IsPlanWithChanges(module.Module.TerragruntOptions.TerraformCliArgs, err) {
+			module.Module.TerragruntOptions.Logger.Debugf("The plan of module %s has changes", module.Module.Path)
+			module.PlanHasChanges = true
+			return nil
+		}
+		return err
 	}
 }
 
@@ -250,6 +369,7 @@ func (module *runningModule) moduleFinished(moduleErr error) {
 
 	module.Status = Finished
 	module.Err = moduleErr
+	module.EndTime = time.Now()
 
 	for _, toNotify := range module.NotifyWhenDone {
 		toNotify.DependencyDone <- module
@@ -270,11 +390,58 @@ func (err DependencyFinishedWithError) Error() string {
 
 func (this DependencyFinishedWithError) ExitStatus() (int, error) {
 	if exitCode, err := shell.GetExitCode(this.Err); err == nil {
+		// The exit code of a plan with changes must not make the module look like it has changes, as it didn't run
+		if exitCode == shell.DetailedExitCodeChanges {
+			return 1, nil
+		}
 		return exitCode, nil
 	}
 	return -1, this
 }
 
+type PlanHasChanges struct {
+	Paths []string
+}
+
+func (err PlanHasChanges) Error() string {
+	return fmt.Sprintf("The plans of %d module(s) have changes: %s", len(err.Paths), strings.Join(err.Paths, ", "))
+}
+
+func (err PlanHasChanges) ExitStatus() (int, error) {
+	return shell.DetailedExitCodeChanges, nil
+}
+
+type ModuleCancelled struct {
+	Module *TerraformModule
+}
+
+func (err ModuleCancelled) Error() string {
+	return fmt.Sprintf("Module %s was not run because the run was cancelled after another module failed", err.Module.Path)
+}
+
+type ModuleTimedOut struct {
+	Path    string
+	Timeout time.Duration
+	Err     error
+}
+
+func (err ModuleTimedOut) Error() string {
+	return fmt.Sprintf("Module %s did not finish within the timeout of %v: %v", err.Path, err.Timeout, err.Err)
+}
+
+func (err ModuleTimedOut) Unwrap() error {
+	return err.Err
+}
+
+type InvalidConcurrencyLimit struct {
+	Group string
+	Limit int
+}
+
+func (err InvalidConcurrencyLimit) Error() string {
+	return fmt.Sprintf("The concurrency limit of group %s must be at least 1, but it is %d", err.Group, err.Limit)
+}
+
 type DependencyNotFoundWhileCrossLinking struct {
 	Module     *runningModule
 	Dependency *TerraformModule
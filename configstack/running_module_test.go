@@ -1,12 +1,18 @@
 package configstack
 
 import (
+	"context"
 	"fmt"
+	"os/exec"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var mockOptions, _ = options.NewTerragruntOptionsForTest("running_module_test")
@@ -1701,3 +1707,234 @@ func TestRemoveFlagExcludedOneExcludeWithDependencies(t *testing.T) {
 
 	assertRunningModuleMapsEqual(t, expected, actual, true)
 }
+
+func TestRunModulesFailFastCancelsRemainingModules(t *testing.T) {
+	t.Parallel()
+
+	expectedErrA := fmt.Errorf("Expected error for module a")
+	expectedErrB := fmt.Errorf("Module b was interrupted")
+
+	// Module a only fails once modules b and d are running, so that they get interrupted.
+	var started sync.WaitGroup
+	started.Add(2)
+
+	// Blocks until the run is cancelled and then returns the given error. Returns a different error if the run isn't
+	// cancelled in time, so the test doesn't hang.
+	waitForCancel := func(toReturn error) func(opts *options.TerragruntOptions) error {
+		return func(opts *options.TerragruntOptions) error {
+			started.Done()
+			select {
+			case <-opts.Context.Done():
+				return toReturn
+			case <-time.After(10 * time.Second):
+				return fmt.Errorf("Run was not cancelled")
+			}
+		}
+	}
+
+	optionsA, err := options.NewTerragruntOptionsForTest("a")
+	assert.NoError(t, err)
+	optionsA.FailFast = true
+	optionsA.RunTerragrunt = func(_ *options.TerragruntOptions) error {
+		started.Wait()
+		return expectedErrA
+	}
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsA,
+	}
+
+	optionsB, err := options.NewTerragruntOptionsForTest("b")
+	assert.NoError(t, err)
+	optionsB.RunTerragrunt = waitForCancel(expectedErrB)
+	moduleB := &TerraformModule{
+		Path:              "b",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsB,
+	}
+
+	optionsD, err := options.NewTerragruntOptionsForTest("d")
+	assert.NoError(t, err)
+	optionsD.RunTerragrunt = waitForCancel(nil)
+	moduleD := &TerraformModule{
+		Path:              "d",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsD,
+	}
+
+	cRan := false
+	moduleC := &TerraformModule{
+		Path:              "c",
+		Dependencies:      []*TerraformModule{moduleD},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "c", nil, &cRan),
+	}
+
+	runningModules, err := toRunningModules([]*TerraformModule{moduleA, moduleB, moduleC, moduleD}, NormalOrder)
+	assert.NoError(t, err)
+
+	err = runModules(context.Background(), runningModules, options.DefaultParallelism)
+	assertMultiErrorContains(t, err, expectedErrA, expectedErrB, ModuleCancelled{moduleC})
+	assert.False(t, cRan)
+}
+
+func TestRunModulesConcurrencyGroupLimits(t *testing.T) {
+	t.Parallel()
+
+	// Tracks how many modules of each group run at the same time
+	var mutex sync.Mutex
+	running := map[string]int{}
+	maxRunning := map[string]int{}
+
+	newModule := func(path string, group string, limits map[string]int) *TerraformModule {
+		opts, err := options.NewTerragruntOptionsForTest(path)
+		require.NoError(t, err)
+		opts.RunTerragrunt = func(_ *options.TerragruntOptions) error {
+			mutex.Lock()
+			running[group]++
+			if running[group] > maxRunning[group] {
+				maxRunning[group] = running[group]
+			}
+			mutex.Unlock()
+
+			time.Sleep(50 * time.Millisecond)
+
+			mutex.Lock()
+			running[group]--
+			mutex.Unlock()
+			return nil
+		}
+		return &TerraformModule{
+			Path:              path,
+			Dependencies:      []*TerraformModule{},
+			Config:            config.TerragruntConfig{ConcurrencyGroup: group, ConcurrencyLimits: limits},
+			TerragruntOptions: opts,
+		}
+	}
+
+	rootLimits := map[string]int{"networking": 1, "account-a": 2}
+	modules := []*TerraformModule{
+		newModule("vpc-1", "networking", rootLimits),
+		newModule("vpc-2", "networking", rootLimits),
+		newModule("vpc-3", "networking", rootLimits),
+		newModule("app-1", "account-a", rootLimits),
+		newModule("app-2", "account-a", rootLimits),
+		newModule("app-3", "account-a", rootLimits),
+		newModule("app-4", "account-a", rootLimits),
+		newModule("other-1", "", nil),
+		newModule("other-2", "", nil),
+		newModule("other-3", "", nil),
+	}
+
+	runningModules, err := toRunningModules(modules, NormalOrder)
+	require.NoError(t, err)
+	require.NoError(t, runModules(context.Background(), runningModules, options.DefaultParallelism))
+
+	assert.Equal(t, 1, maxRunning["networking"])
+	assert.Equal(t, 2, maxRunning["account-a"])
+	assert.Equal(t, 3, maxRunning[""])
+}
+
+func TestConcurrencyGroupSemaphoresLimits(t *testing.T) {
+	t.Parallel()
+
+	newModule := func(path string, limits map[string]int, cliLimits map[string]int) *TerraformModule {
+		opts, err := options.NewTerragruntOptionsForTest(path)
+		require.NoError(t, err)
+		opts.ConcurrencyLimits = cliLimits
+		return &TerraformModule{
+			Path:              path,
+			Config:            config.TerragruntConfig{ConcurrencyGroup: "networking", ConcurrencyLimits: limits},
+			TerragruntOptions: opts,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		modules       []*TerraformModule
+		expectedLimit int
+		expectedErr   bool
+	}{
+		{"config-limit", []*TerraformModule{newModule("a", map[string]int{"networking": 2}, nil)}, 2, false},
+		{"lowest-config-limit-wins", []*TerraformModule{newModule("a", map[string]int{"networking": 2}, nil), newModule("b", map[string]int{"networking": 3}, nil)}, 2, false},
+		{"cli-overrides-config", []*TerraformModule{newModule("a", map[string]int{"networking": 2}, map[string]int{"networking": 5})}, 5, false},
+		{"no-limit", []*TerraformModule{newModule("a", nil, nil)}, 0, false},
+		{"invalid-limit", []*TerraformModule{newModule("a", nil, map[string]int{"networking": 0})}, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			runningModules, err := toRunningModules(testCase.modules, NormalOrder)
+			require.NoError(t, err)
+
+			semaphores, err := concurrencyGroupSemaphores(runningModules)
+			if testCase.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedLimit, cap(semaphores["networking"]))
+		})
+	}
+}
+
+func TestRunModulesDetailedExitCode(t *testing.T) {
+	t.Parallel()
+
+	exitErr := func(code string) error {
+		err := exec.Command("sh", "-c", "exit "+code).Run()
+		require.Error(t, err)
+		return shell.ProcessExecutionError{Err: err}
+	}
+
+	newModule := func(path string, toReturn error, executed *bool, dependencies ...*TerraformModule) *TerraformModule {
+		opts := optionsWithMockTerragruntCommand(t, path, toReturn, executed)
+		opts.TerraformCliArgs = []string{"plan", "-input=false", "-detailed-exitcode"}
+		return &TerraformModule{Path: path, Dependencies: dependencies, Config: config.TerragruntConfig{}, TerragruntOptions: opts}
+	}
+
+	aRan := false
+	moduleA := newModule("a", exitErr("2"), &aRan)
+	bRan := false
+	moduleB := newModule("b", nil, &bRan, moduleA)
+
+	modules := []*TerraformModule{moduleA, moduleB}
+	runningModules, err := toRunningModules(modules, NormalOrder)
+	require.NoError(t, err)
+	require.NoError(t, runModules(context.Background(), runningModules, options.DefaultParallelism))
+	assert.True(t, bRan)
+	assert.Equal(t, []string{"a"}, modulesWithPlanChanges(runningModules))
+
+	report := newRunReport("plan", time.Now(), time.Now(), modules, runningModules)
+	require.NotNil(t, report.Modules[0].ExitCode)
+	assert.Equal(t, ModuleSucceeded, report.Modules[0].Status)
+	assert.Equal(t, 2, *report.Modules[0].ExitCode)
+
+	exitCode, err := shell.GetExitCode(PlanHasChanges{Paths: []string{"a"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, exitCode)
+
+	// Without -detailed-exitcode, exit code 2 is a failure that blocks the dependents
+	cRan := false
+	moduleC := newModule("c", exitErr("2"), &cRan)
+	moduleC.TerragruntOptions.TerraformCliArgs = []string{"apply"}
+	dRan := false
+	moduleD := newModule("d", nil, &dRan, moduleC)
+
+	runningModules, err = toRunningModules([]*TerraformModule{moduleC, moduleD}, NormalOrder)
+	require.NoError(t, err)
+	assert.Error(t, runModules(context.Background(), runningModules, options.DefaultParallelism))
+	assert.False(t, dRan)
+
+	dependencyErr := runningModules["d"].Err.(DependencyFinishedWithError)
+	exitCode, err = dependencyErr.ExitStatus()
+	require.NoError(t, err)
+	assert.Equal(t, 1, exitCode)
+}
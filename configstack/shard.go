@@ -0,0 +1,316 @@
+package configstack
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The env vars that hold the ID of the current run in the CI systems whose ID the shards use by default, as
+// --terragrunt-shard-run-id, in the order they are checked.
+var ciRunIDEnvVars = []string{
+	"GITHUB_RUN_ID",      // GitHub Actions
+	"CI_PIPELINE_ID",     // GitLab CI
+	"BUILDKITE_BUILD_ID", // Buildkite
+	"CIRCLE_WORKFLOW_ID", // CircleCI
+	"BUILD_BUILDID",      // Azure Pipelines
+}
+
+// runShard is the part of a stack that one worker runs in a distributed run-all, where the modules of the stack are
+// split across several workers. Workers learn that the modules run by other workers have finished through the done
+// markers in a shared DoneMarkerStore.
+type runShard struct {
+	// The 1-based index of this shard, and the total number of shards
+	Index int
+	Count int
+
+	// The ID of the run, shared by all its shards, under which the done markers are recorded
+	RunID string
+
+	Command      string
+	StackPath    string
+	Store        DoneMarkerStore
+	PollInterval time.Duration
+
+	// The maximum time to wait for a module to finish on another shard. 0 means no limit.
+	WaitTimeout time.Duration
+
+	// The index of the shard that runs each module, keyed by module path
+	assignments map[string]int
+}
+
+// ParseShard parses a shard given as "index/count", such as "2/5", where the index is between 1 and the count.
+func ParseShard(shard string) (int, int, error) {
+	parts := strings.Split(shard, "/")
+	if len(parts) != 2 {
+		return 0, 0, errors.WithStackTrace(InvalidShard(shard))
+	}
+
+	index, indexErr := strconv.Atoi(strings.TrimSpace(parts[0]))
+	count, countErr := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if indexErr != nil || countErr != nil || count < 1 || index < 1 || index > count {
+		return 0, 0, errors.WithStackTrace(InvalidShard(shard))
+	}
+
+	return index, count, nil
+}
+
+// newRunShard returns the shard of the stack that this worker runs, as set with --terragrunt-shard, or nil if the
+// run-all is not distributed.
+func newRunShard(stack *Stack, terragruntOptions *options.TerragruntOptions, dependencyOrder DependencyOrder) (*runShard, error) {
+	if terragruntOptions.Shard == "" {
+		return nil, nil
+	}
+
+	index, count, err := ParseShard(terragruntOptions.Shard)
+	if err != nil {
+		return nil, err
+	}
+
+	if terragruntOptions.ShardMarkerStore == "" {
+		return nil, errors.WithStackTrace(MissingShardMarkerStore{})
+	}
+
+	runID, err := shardRunID(terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var waitTimeout time.Duration
+	if terragruntOptions.ShardWaitTimeout != "" {
+		waitTimeout, err = time.ParseDuration(terragruntOptions.ShardWaitTimeout)
+		if err != nil || waitTimeout <= 0 {
+			return nil, errors.WithStackTrace(InvalidShardWaitTimeout(terragruntOptions.ShardWaitTimeout))
+		}
+	}
+
+	store, err := NewDoneMarkerStore(terragruntOptions.ShardMarkerStore, terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	shard := &runShard{
+		Index:        index,
+		Count:        count,
+		RunID:        runID,
+		Command:      terragruntOptions.TerraformCommand,
+		StackPath:    stack.Path,
+		Store:        store,
+		PollInterval: terragruntOptions.ShardPollInterval,
+		WaitTimeout:  waitTimeout,
+	}
+	if err := shard.assignModules(stack, dependencyOrder); err != nil {
+		return nil, err
+	}
+	return shard, nil
+}
+
+// Returns the run ID set with --terragrunt-shard-run-id or, if none is set, the ID of the CI run. Fails if neither is
+// set, as the markers left in the store by earlier runs would be mistaken for modules that already finished.
+func shardRunID(terragruntOptions *options.TerragruntOptions) (string, error) {
+	runID := terragruntOptions.ShardRunID
+	for _, envVar := range ciRunIDEnvVars {
+		if runID != "" {
+			break
+		}
+		runID = terragruntOptions.Env[envVar]
+	}
+
+	if runID == "" {
+		return "", errors.WithStackTrace(MissingShardRunID{})
+	}
+	// The ID is a single segment of the marker keys
+	if strings.Contains(runID, "/") || runID == "." || runID == ".." {
+		return "", errors.WithStackTrace(InvalidShardRunID(runID))
+	}
+	return runID, nil
+}
+
+// assignModules deterministically assigns each module of the stack to a shard. The modules of each group of the run
+// graph are sorted by path and dealt to the shards in turn, so that each shard gets a similar share of the work that
+// can run at the same time. Modules that are not in the run graph, because they are assumed to be already applied,
+// finish immediately, so every shard keeps them.
+func (shard *runShard) assignModules(stack *Stack, dependencyOrder DependencyOrder) error {
+	var groups [][]*TerraformModule
+	if dependencyOrder == IgnoreOrder {
+		group := []*TerraformModule{}
+		for _, module := range stack.Modules {
+			if !module.FlagExcluded && !module.AssumeAlreadyApplied {
+				group = append(group, module)
+			}
+		}
+		sort.Slice(group, func(i, j int) bool { return group[i].Path < group[j].Path })
+		groups = [][]*TerraformModule{group}
+	} else {
+		command := ""
+		if dependencyOrder == ReverseOrder {
+			command = "destroy"
+		}
+
+		runGraph, err := stack.getModuleRunGraph(command)
+		if err != nil {
+			return err
+		}
+		groups = runGraph
+	}
+
+	shard.assignments = map[string]int{}
+	for _, group := range groups {
+		for i, module := range group {
+			shard.assignments[module.Path] = i%shard.Count + 1
+		}
+	}
+	return nil
+}
+
+// runsLocally returns true if the module at the given path is run by this shard.
+func (shard *runShard) runsLocally(modulePath string) bool {
+	index, isAssigned := shard.assignments[modulePath]
+	return !isAssigned || index == shard.Index
+}
+
+// attach makes the given running modules take part in the shard: the modules run by this shard record a done marker
+// when they finish, and the modules run by other shards wait for their marker instead of running.
+func (shard *runShard) attach(modules map[string]*runningModule) {
+	for _, module := range modules {
+		module.Shard = shard
+		module.RunElsewhere = !shard.runsLocally(module.Module.Path)
+	}
+}
+
+// Returns the path of the given module relative to the stack, which identifies the module in the same way on all shards,
+// even if they check out the code in different folders.
+func (shard *runShard) moduleID(module *TerraformModule) string {
+	relPath, err := util.GetPathRelativeTo(module.Path, shard.StackPath)
+	if err != nil {
+		return module.Path
+	}
+	return relPath
+}
+
+// Returns the key of the done marker of the given module in the store, which is specific to the run, so that the markers
+// of other runs are ignored.
+func (shard *runShard) markerKey(module *TerraformModule) string {
+	return path.Join(shard.RunID, shard.Command, shard.moduleID(module), "done.json")
+}
+
+// markDone records in the store that the given module, run by this shard, finished with the given error.
+func (shard *runShard) markDone(module *TerraformModule, moduleErr error) error {
+	marker := &DoneMarker{
+		Module:    shard.moduleID(module),
+		Shard:     shard.Index,
+		Succeeded: moduleErr == nil,
+	}
+	if moduleErr != nil {
+		marker.Error = moduleErr.Error()
+	}
+	return shard.Store.Write(shard.markerKey(module), marker)
+}
+
+// waitForModule waits until the done marker of the given module, which is run by another shard, shows up in the store,
+// and returns an error if the module failed on that shard, or if it didn't finish within the wait timeout, such as when
+// the other shard died. Returns immediately if no module of this shard depends on the module, as there is nothing to
+// wait for.
+func (shard *runShard) waitForModule(ctx context.Context, module *runningModule) error {
+	hasLocalDependents := false
+	for _, dependent := range module.NotifyWhenDone {
+		if !dependent.RunElsewhere {
+			hasLocalDependents = true
+			break
+		}
+	}
+	if !hasLocalDependents {
+		return nil
+	}
+
+	logger := module.Module.TerragruntOptions.Logger
+	logger.Infof("Waiting for module %s to finish on shard %d/%d", module.Module.Path, shard.assignments[module.Module.Path], shard.Count)
+
+	var timeout <-chan time.Time
+	if shard.WaitTimeout > 0 {
+		timeout = time.After(shard.WaitTimeout)
+	}
+
+	for {
+		marker, err := shard.Store.Read(shard.markerKey(module.Module))
+		if err != nil {
+			return err
+		}
+		if marker != nil {
+			if !marker.Succeeded {
+				return errors.WithStackTrace(ModuleFailedOnOtherShard{Path: module.Module.Path, Shard: marker.Shard, Err: marker.Error})
+			}
+			logger.Debugf("Module %s finished successfully on shard %d/%d", module.Module.Path, marker.Shard, shard.Count)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ModuleCancelled{module.Module}
+		case <-timeout:
+			return errors.WithStackTrace(ShardWaitTimedOut{Path: module.Module.Path, Shard: shard.assignments[module.Module.Path], Count: shard.Count, Timeout: shard.WaitTimeout})
+		case <-time.After(shard.PollInterval):
+		}
+	}
+}
+
+// Custom error types
+
+type InvalidShard string
+
+func (shard InvalidShard) Error() string {
+	return fmt.Sprintf("Invalid shard %q. Expected index/count, such as 2/5, with an index between 1 and the count.", string(shard))
+}
+
+type MissingShardMarkerStore struct{}
+
+func (err MissingShardMarkerStore) Error() string {
+	return "A done marker store must be set with --terragrunt-shard-marker-store to split a run-all across shards."
+}
+
+type MissingShardRunID struct{}
+
+func (err MissingShardRunID) Error() string {
+	return "A run ID, shared by all the shards of the run, must be set with --terragrunt-shard-run-id to split a run-all across shards, as the ID of the CI run could not be found in the environment."
+}
+
+type InvalidShardRunID string
+
+func (runID InvalidShardRunID) Error() string {
+	return fmt.Sprintf("Invalid shard run ID %q. The ID can't contain / or be . or ..", string(runID))
+}
+
+type InvalidShardWaitTimeout string
+
+func (timeout InvalidShardWaitTimeout) Error() string {
+	return fmt.Sprintf("Invalid shard wait timeout %q. Expected a positive duration, such as 2h or 30m.", string(timeout))
+}
+
+type ShardWaitTimedOut struct {
+	Path    string
+	Shard   int
+	Count   int
+	Timeout time.Duration
+}
+
+func (err ShardWaitTimedOut) Error() string {
+	return fmt.Sprintf("Module %s did not finish on shard %d/%d within %v. Check that the shard is still running.", err.Path, err.Shard, err.Count, err.Timeout)
+}
+
+type ModuleFailedOnOtherShard struct {
+	Path  string
+	Shard int
+	Err   string
+}
+
+func (err ModuleFailedOnOtherShard) Error() string {
+	return fmt.Sprintf("Module %s failed on shard %d: %s", err.Path, err.Shard, err.Err)
+}
@@ -0,0 +1,328 @@
+package configstack
+
+import (
+	"context"
+	goerrors "errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseShard(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		shard         string
+		expectedIndex int
+		expectedCount int
+		expectedErr   bool
+	}{
+		{"1/1", 1, 1, false},
+		{"2/5", 2, 5, false},
+		{"5/5", 5, 5, false},
+		{"0/5", 0, 0, true},
+		{"6/5", 0, 0, true},
+		{"2", 0, 0, true},
+		{"a/b", 0, 0, true},
+		{"1/0", 0, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		index, count, err := ParseShard(testCase.shard)
+		if testCase.expectedErr {
+			assert.Error(t, err, testCase.shard)
+			continue
+		}
+		require.NoError(t, err, testCase.shard)
+		assert.Equal(t, testCase.expectedIndex, index, testCase.shard)
+		assert.Equal(t, testCase.expectedCount, count, testCase.shard)
+	}
+}
+
+func TestLocalDoneMarkerStore(t *testing.T) {
+	t.Parallel()
+
+	store := &localDoneMarkerStore{Dir: t.TempDir()}
+
+	marker, err := store.Read("apply/vpc/done.json")
+	require.NoError(t, err)
+	assert.Nil(t, marker)
+
+	require.NoError(t, store.Write("apply/vpc/done.json", &DoneMarker{Module: "vpc", Shard: 2, Succeeded: true}))
+
+	marker, err = store.Read("apply/vpc/done.json")
+	require.NoError(t, err)
+	assert.Equal(t, &DoneMarker{Module: "vpc", Shard: 2, Succeeded: true}, marker)
+}
+
+// shardTestRun records when the mock terragrunt command of each module started and finished, across shards.
+type shardTestRun struct {
+	mutex     sync.Mutex
+	started   map[string]time.Time
+	finished  map[string]time.Time
+	failPaths map[string]bool
+}
+
+// Returns the modules of a stack that shards split as follows:
+//
+//	group 1: a (shard 1), b (shard 2)
+//	group 2: c (shard 1), which depends on a and b
+//	group 3: d (shard 1), which depends on c
+func (run *shardTestRun) newStack(t *testing.T) *Stack {
+	newModule := func(name string, dependencies ...*TerraformModule) *TerraformModule {
+		path := "/stack/" + name
+		opts, err := options.NewTerragruntOptionsForTest(path)
+		require.NoError(t, err)
+		opts.RunTerragrunt = func(_ *options.TerragruntOptions) error {
+			run.mutex.Lock()
+			run.started[path] = time.Now()
+			run.mutex.Unlock()
+
+			time.Sleep(50 * time.Millisecond)
+
+			run.mutex.Lock()
+			defer run.mutex.Unlock()
+			run.finished[path] = time.Now()
+			if run.failPaths[path] {
+				return goerrors.New("failed " + path)
+			}
+			return nil
+		}
+		return &TerraformModule{Path: path, Dependencies: dependencies, Config: config.TerragruntConfig{}, TerragruntOptions: opts}
+	}
+
+	a := newModule("a")
+	b := newModule("b")
+	c := newModule("c", a, b)
+	d := newModule("d", c)
+	return &Stack{Path: "/stack", Modules: []*TerraformModule{a, b, c, d}}
+}
+
+// Runs the given number of shards of the stack concurrently and returns the running modules and error of each shard.
+func (run *shardTestRun) runShards(t *testing.T, count int) ([]map[string]*runningModule, []error) {
+	storeDir := t.TempDir()
+
+	allRunningModules := make([]map[string]*runningModule, count)
+	errs := make([]error, count)
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < count; i++ {
+		stack := run.newStack(t)
+
+		terragruntOptions, err := options.NewTerragruntOptionsForTest("/stack")
+		require.NoError(t, err)
+		terragruntOptions.TerraformCommand = "apply"
+		terragruntOptions.Shard = []string{"1/2", "2/2"}[i]
+		terragruntOptions.ShardMarkerStore = storeDir
+		terragruntOptions.ShardRunID = "run-1"
+		terragruntOptions.ShardPollInterval = 10 * time.Millisecond
+
+		shard, err := newRunShard(stack, terragruntOptions, NormalOrder)
+		require.NoError(t, err)
+
+		runningModules, err := toRunningModules(stack.Modules, NormalOrder)
+		require.NoError(t, err)
+		shard.attach(runningModules)
+		allRunningModules[i] = runningModules
+
+		waitGroup.Add(1)
+		go func(i int) {
+			defer waitGroup.Done()
+			errs[i] = runModules(context.Background(), runningModules, options.DefaultParallelism)
+		}(i)
+	}
+	waitGroup.Wait()
+
+	return allRunningModules, errs
+}
+
+func TestShardedRunModules(t *testing.T) {
+	t.Parallel()
+
+	run := &shardTestRun{started: map[string]time.Time{}, finished: map[string]time.Time{}, failPaths: map[string]bool{}}
+	runningModules, errs := run.runShards(t, 2)
+
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+
+	// Every module ran exactly once, on the shard it was assigned to
+	assert.Len(t, run.finished, 4)
+	assert.False(t, runningModules[0]["/stack/a"].RunElsewhere)
+	assert.True(t, runningModules[0]["/stack/b"].RunElsewhere)
+	assert.False(t, runningModules[0]["/stack/c"].RunElsewhere)
+	assert.False(t, runningModules[0]["/stack/d"].RunElsewhere)
+	assert.False(t, runningModules[1]["/stack/b"].RunElsewhere)
+	assert.True(t, runningModules[1]["/stack/c"].RunElsewhere)
+
+	// c waited for b, which ran on the other shard
+	assert.True(t, run.started["/stack/c"].After(run.finished["/stack/b"]))
+	assert.True(t, run.started["/stack/d"].After(run.finished["/stack/c"]))
+
+	assert.Equal(t, ModuleOtherShard, newModuleReport(runningModules[0]["/stack/b"]).Status)
+	assert.Equal(t, ModuleSucceeded, newModuleReport(runningModules[1]["/stack/b"]).Status)
+}
+
+func TestShardedRunModulesFailureOnOtherShard(t *testing.T) {
+	t.Parallel()
+
+	run := &shardTestRun{started: map[string]time.Time{}, finished: map[string]time.Time{}, failPaths: map[string]bool{"/stack/b": true}}
+	runningModules, errs := run.runShards(t, 2)
+
+	assert.Error(t, errs[0])
+	assert.Error(t, errs[1])
+
+	_, cRan := run.started["/stack/c"]
+	assert.False(t, cRan)
+
+	var otherShardErr ModuleFailedOnOtherShard
+	require.True(t, goerrors.As(runningModules[0]["/stack/b"].Err, &otherShardErr))
+	assert.Equal(t, "/stack/b", otherShardErr.Path)
+	assert.Equal(t, 2, otherShardErr.Shard)
+
+	var dependencyErr DependencyFinishedWithError
+	require.True(t, goerrors.As(runningModules[0]["/stack/c"].Err, &dependencyErr))
+	assert.Equal(t, "/stack/b", dependencyErr.Dependency.Path)
+	assert.True(t, goerrors.As(runningModules[0]["/stack/d"].Err, &dependencyErr))
+}
+
+func TestShardedRunModulesWaitTimeout(t *testing.T) {
+	t.Parallel()
+
+	// Only the first shard runs, as if the second one died, so b never finishes
+	run := &shardTestRun{started: map[string]time.Time{}, finished: map[string]time.Time{}, failPaths: map[string]bool{}}
+	stack := run.newStack(t)
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("/stack")
+	require.NoError(t, err)
+	terragruntOptions.TerraformCommand = "apply"
+	terragruntOptions.Shard = "1/2"
+	terragruntOptions.ShardMarkerStore = t.TempDir()
+	terragruntOptions.ShardRunID = "run-1"
+	terragruntOptions.ShardPollInterval = 10 * time.Millisecond
+	terragruntOptions.ShardWaitTimeout = "100ms"
+
+	shard, err := newRunShard(stack, terragruntOptions, NormalOrder)
+	require.NoError(t, err)
+	runningModules, err := toRunningModules(stack.Modules, NormalOrder)
+	require.NoError(t, err)
+	shard.attach(runningModules)
+
+	assert.Error(t, runModules(context.Background(), runningModules, options.DefaultParallelism))
+
+	var timedOutErr ShardWaitTimedOut
+	require.True(t, goerrors.As(runningModules["/stack/b"].Err, &timedOutErr))
+	assert.Equal(t, "/stack/b", timedOutErr.Path)
+	assert.Equal(t, 2, timedOutErr.Shard)
+
+	_, cRan := run.started["/stack/c"]
+	assert.False(t, cRan)
+	var dependencyErr DependencyFinishedWithError
+	assert.True(t, goerrors.As(runningModules["/stack/c"].Err, &dependencyErr))
+}
+
+func TestNewRunShardInvalidWaitTimeout(t *testing.T) {
+	t.Parallel()
+
+	run := &shardTestRun{}
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("/stack")
+	require.NoError(t, err)
+	terragruntOptions.Shard = "1/2"
+	terragruntOptions.ShardMarkerStore = t.TempDir()
+	terragruntOptions.ShardRunID = "run-1"
+	terragruntOptions.ShardWaitTimeout = "forever"
+
+	_, err = newRunShard(run.newStack(t), terragruntOptions, NormalOrder)
+	var invalidTimeoutErr InvalidShardWaitTimeout
+	assert.True(t, goerrors.As(err, &invalidTimeoutErr))
+}
+
+func TestNewRunShardRequiresMarkerStore(t *testing.T) {
+	t.Parallel()
+
+	run := &shardTestRun{}
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("/stack")
+	require.NoError(t, err)
+	terragruntOptions.Shard = "1/2"
+
+	_, err = newRunShard(run.newStack(t), terragruntOptions, NormalOrder)
+	var missingStoreErr MissingShardMarkerStore
+	assert.True(t, goerrors.As(err, &missingStoreErr))
+
+	terragruntOptions.Shard = ""
+	shard, err := newRunShard(run.newStack(t), terragruntOptions, NormalOrder)
+	require.NoError(t, err)
+	assert.Nil(t, shard)
+}
+
+func TestNewRunShardRunID(t *testing.T) {
+	t.Parallel()
+
+	run := &shardTestRun{}
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("/stack")
+	require.NoError(t, err)
+	terragruntOptions.TerraformCommand = "apply"
+	terragruntOptions.Shard = "1/2"
+	terragruntOptions.ShardMarkerStore = t.TempDir()
+	terragruntOptions.Env = map[string]string{}
+
+	_, err = newRunShard(run.newStack(t), terragruntOptions, NormalOrder)
+	var missingRunIDErr MissingShardRunID
+	assert.True(t, goerrors.As(err, &missingRunIDErr))
+
+	// The ID of the CI run is used by default
+	terragruntOptions.Env = map[string]string{"CI_PIPELINE_ID": "1234"}
+	shard, err := newRunShard(run.newStack(t), terragruntOptions, NormalOrder)
+	require.NoError(t, err)
+	assert.Equal(t, "1234", shard.RunID)
+	assert.Equal(t, "1234/apply/a/done.json", shard.markerKey(&TerraformModule{Path: "/stack/a"}))
+
+	terragruntOptions.ShardRunID = "run-1"
+	shard, err = newRunShard(run.newStack(t), terragruntOptions, NormalOrder)
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", shard.RunID)
+
+	terragruntOptions.ShardRunID = "../run-1"
+	_, err = newRunShard(run.newStack(t), terragruntOptions, NormalOrder)
+	var invalidRunIDErr InvalidShardRunID
+	assert.True(t, goerrors.As(err, &invalidRunIDErr))
+}
+
+func TestShardedRunModulesIgnoresMarkersOfOtherRuns(t *testing.T) {
+	t.Parallel()
+
+	// Only the first shard runs, while the store has the marker that b finished in an earlier run
+	run := &shardTestRun{started: map[string]time.Time{}, finished: map[string]time.Time{}, failPaths: map[string]bool{}}
+	stack := run.newStack(t)
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("/stack")
+	require.NoError(t, err)
+	terragruntOptions.TerraformCommand = "apply"
+	terragruntOptions.Shard = "1/2"
+	terragruntOptions.ShardMarkerStore = t.TempDir()
+	terragruntOptions.ShardPollInterval = 10 * time.Millisecond
+	terragruntOptions.ShardWaitTimeout = "100ms"
+
+	terragruntOptions.ShardRunID = "run-1"
+	earlierShard, err := newRunShard(stack, terragruntOptions, NormalOrder)
+	require.NoError(t, err)
+	require.NoError(t, earlierShard.markDone(stack.Modules[1], nil))
+
+	terragruntOptions.ShardRunID = "run-2"
+	shard, err := newRunShard(stack, terragruntOptions, NormalOrder)
+	require.NoError(t, err)
+	runningModules, err := toRunningModules(stack.Modules, NormalOrder)
+	require.NoError(t, err)
+	shard.attach(runningModules)
+
+	assert.Error(t, runModules(context.Background(), runningModules, options.DefaultParallelism))
+
+	var timedOutErr ShardWaitTimedOut
+	assert.True(t, goerrors.As(runningModules["/stack/b"].Err, &timedOutErr))
+	_, cRan := run.started["/stack/c"]
+	assert.False(t, cRan)
+}
@@ -2,14 +2,18 @@ package configstack
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/shell"
 	"github.com/gruntwork-io/terragrunt/util"
 	"github.com/sirupsen/logrus"
 )
@@ -51,9 +55,39 @@ func (stack *Stack) LogModuleDeployOrder(logger *logrus.Entry, terraformCommand
 	return nil
 }
 
-// Graph creates a graphviz representation of the modules
-func (stack *Stack) Graph(terragruntOptions *options.TerragruntOptions) {
-	WriteDot(terragruntOptions.Writer, terragruntOptions, stack.Modules)
+// Graph writes the dependency graph of the modules in the format set with --format, which defaults to a graphviz
+// representation. The modules are coloured by their result in the run report set with --run-report, if any.
+func (stack *Stack) Graph(terragruntOptions *options.TerragruntOptions) error {
+	var report *RunReport
+	if terragruntOptions.GraphRunReport != "" {
+		var err error
+		if report, err = ReadRunReport(terragruntOptions.GraphRunReport); err != nil {
+			return err
+		}
+	}
+
+	stack.readModuleFlags(terragruntOptions)
+
+	return WriteGraph(terragruntOptions.Writer, terragruntOptions, stack.Modules, terragruntOptions.GraphFormat, report)
+}
+
+// readModuleFlags parses the skip and prevent_destroy attributes of the modules, which are not needed, and so not
+// parsed, to find the stack. Modules whose flags can't be parsed are shown without them.
+func (stack *Stack) readModuleFlags(terragruntOptions *options.TerragruntOptions) {
+	for _, module := range stack.Modules {
+		flags, err := config.PartialParseConfigFile(
+			module.TerragruntOptions.TerragruntConfigPath,
+			module.TerragruntOptions,
+			nil,
+			[]config.PartialDecodeSectionType{config.TerragruntFlags},
+		)
+		if err != nil {
+			terragruntOptions.Logger.Warnf("Could not parse the skip and prevent_destroy attributes of module %s: %v", module.Path, err)
+			continue
+		}
+		module.Config.Skip = flags.Skip
+		module.Config.PreventDestroy = flags.PreventDestroy
+	}
 }
 
 func (stack *Stack) Run(terragruntOptions *options.TerragruntOptions) error {
@@ -75,12 +109,26 @@ func (stack *Stack) Run(terragruntOptions *options.TerragruntOptions) error {
 	case "apply", "destroy":
 		// to support potential positional args in the args list, we append the input=false arg after the first element,
 		// which is the target command.
-		if terragruntOptions.RunAllAutoApprove {
+		if terragruntOptions.RunAllAutoApprove && !terragruntOptions.RunAllConfirmEach {
 			terragruntOptions.TerraformCliArgs = util.StringListInsert(terragruntOptions.TerraformCliArgs, "-auto-approve", 1)
 		}
 		stack.syncTerraformCliArgs(terragruntOptions)
 	}
 
+	switch terragruntOptions.OutputMode {
+	case "", OutputModeStream:
+	case OutputModeBuffered:
+		// With --terragrunt-run-all-confirm-each, the plans are already shown one module at a time
+		if !terragruntOptions.RunAllConfirmEach || (stackCmd != "apply" && stackCmd != "destroy") {
+			if reason := promptReason(terragruntOptions); reason != "" {
+				return errors.WithStackTrace(BufferedOutputWithPrompts(reason))
+			}
+			stack.prepareBufferedOutput()
+		}
+	default:
+		return errors.WithStackTrace(UnsupportedOutputMode(terragruntOptions.OutputMode))
+	}
+
 	if stackCmd == "plan" {
 		// We capture the out stream for each module
 		errorStreams := make([]bytes.Buffer, len(stack.Modules))
@@ -94,13 +142,133 @@ func (stack *Stack) Run(terragruntOptions *options.TerragruntOptions) error {
 		defer stack.summarizePlanAllErrors(terragruntOptions, errorStreams)
 	}
 
+	planSummaryDir := ""
+	if stackCmd == "plan" && terragruntOptions.PlanSummary {
+		dir, err := stack.preparePlanSummary()
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		planSummaryDir = dir
+	}
+
+	if terragruntOptions.RunAllConfirmEach && (stackCmd == "apply" || stackCmd == "destroy") {
+		dir, err := stack.prepareConfirmEach(shell.PromptUserForYesNo)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+	}
+
+	var drifts *driftResults
+	if stackCmd == DriftCommand {
+		drifts = stack.prepareDriftDetection()
+	}
+
+	var merged *mergedOutputs
+	if stackCmd == "output" && terragruntOptions.MergeOutputJSON {
+		merged = stack.prepareMergedOutputs(config.GetOutputJson)
+	}
+
+	dependencyOrder := NormalOrder
 	if terragruntOptions.IgnoreDependencyOrder {
-		return RunModulesIgnoreOrder(stack.Modules, terragruntOptions.Parallelism)
+		dependencyOrder = IgnoreOrder
 	} else if stackCmd == "destroy" {
-		return RunModulesReverseOrder(stack.Modules, terragruntOptions.Parallelism)
-	} else {
-		return RunModules(stack.Modules, terragruntOptions.Parallelism)
+		dependencyOrder = ReverseOrder
+	}
+
+	runningModules, err := toRunningModules(stack.Modules, dependencyOrder)
+	if err != nil {
+		return err
 	}
+
+	shard, err := newRunShard(stack, terragruntOptions, dependencyOrder)
+	if err != nil {
+		return err
+	}
+	if shard != nil {
+		shard.attach(runningModules)
+		terragruntOptions.Logger.Infof("Running shard %d/%d of the stack at %s", shard.Index, shard.Count, stack.Path)
+	}
+
+	hooks, err := readStackHooks(terragruntOptions, stackCmd)
+	if err != nil {
+		return err
+	}
+	if err := hooks.runBefore(terragruntOptions, stack); err != nil {
+		return err
+	}
+
+	startTime := time.Now()
+	ctx := terragruntOptions.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	retryFailed := terragruntOptions.RunAllRetryMaxAttempts > 1 && !terragruntOptions.FailFast
+	if retryFailed {
+		// The signals must stop the retries, so they are trapped instead of terminating Terragrunt, and cancel the run
+		// instead: the modules that did not start yet are skipped and no more retries are run.
+		var stopOnSignals context.CancelFunc
+		ctx, stopOnSignals = shell.NotifyForwardedSignals(ctx)
+		defer stopOnSignals()
+	}
+
+	runErr := runModules(ctx, runningModules, terragruntOptions.Parallelism)
+	if runErr != nil && retryFailed {
+		runErr = retryFailedModules(ctx, terragruntOptions, runningModules, dependencyOrder, shard, runErr)
+	}
+
+	if err := stack.saveCheckpoint(terragruntOptions, runningModules); err != nil {
+		terragruntOptions.Logger.Errorf("Failed to save run-all checkpoint: %v", err)
+	}
+
+	if merged != nil && runErr == nil {
+		runErr = merged.Write(terragruntOptions.Writer)
+	}
+
+	if changedPaths := modulesWithPlanChanges(runningModules); runErr == nil && len(changedPaths) > 0 {
+		runErr = errors.WithStackTrace(PlanHasChanges{Paths: changedPaths})
+	}
+
+	if drifts != nil {
+		if err := stack.writeDriftTable(terragruntOptions.Writer, runningModules, drifts.drifts); err != nil {
+			terragruntOptions.Logger.Errorf("Failed to write drift report: %v", err)
+		}
+		if driftedPaths := drifts.driftedPaths(); runErr == nil && len(driftedPaths) > 0 {
+			runErr = errors.WithStackTrace(DriftDetected{Paths: driftedPaths})
+		}
+	}
+
+	var planSummaries map[string]*PlanSummary
+	if planSummaryDir != "" {
+		planSummaries = stack.readPlanSummaries(terragruntOptions, runningModules)
+		if err := stack.writePlanSummaryTable(terragruntOptions.Writer, runningModules, planSummaries); err != nil {
+			terragruntOptions.Logger.Errorf("Failed to write plan summary: %v", err)
+		}
+	}
+
+	report := newRunReport(stackCmd, startTime, time.Now(), stack.Modules, runningModules)
+	report.setPlanSummaries(planSummaries)
+	if drifts != nil {
+		report.setDrifts(drifts.drifts)
+	}
+
+	if terragruntOptions.ReportFile != "" {
+		if err := report.WriteToFile(terragruntOptions.ReportFile, terragruntOptions.ReportFormat); err != nil {
+			terragruntOptions.Logger.Errorf("Failed to write run report to %s: %v", terragruntOptions.ReportFile, err)
+			if runErr == nil {
+				runErr = err
+			}
+		} else {
+			terragruntOptions.Logger.Infof("Run report written to %s", terragruntOptions.ReportFile)
+		}
+	}
+
+	if err := hooks.runAfter(terragruntOptions, stack, report, runErr); err != nil && runErr == nil {
+		runErr = err
+	}
+
+	return runErr
 }
 
 // We inspect the error streams to give an explicit message if the plan failed because there were references to
@@ -260,3 +428,9 @@ type DependencyCycle []string
 func (err DependencyCycle) Error() string {
 	return fmt.Sprintf("Found a dependency cycle between modules: %s", strings.Join([]string(err), " -> "))
 }
+
+type ModuleNotFound string
+
+func (path ModuleNotFound) Error() string {
+	return fmt.Sprintf("Could not find a module with a Terragrunt configuration file in %s", string(path))
+}
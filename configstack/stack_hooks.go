@@ -0,0 +1,200 @@
+package configstack
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/errors"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The environment variables through which stack hooks get the details of the run-all invocation. The result, error and
+// report variables are only set for the after commands.
+const (
+	stackHookEnvCommand    = "TERRAGRUNT_STACK_COMMAND"
+	stackHookEnvPath       = "TERRAGRUNT_STACK_PATH"
+	stackHookEnvResult     = "TERRAGRUNT_STACK_RESULT"
+	stackHookEnvError      = "TERRAGRUNT_STACK_ERROR"
+	stackHookEnvReportFile = "TERRAGRUNT_STACK_REPORT_FILE"
+)
+
+// The values of TERRAGRUNT_STACK_RESULT.
+const (
+	stackHookResultSuccess = "success"
+	stackHookResultFailure = "failure"
+)
+
+// stackHooks are the stack_hook blocks of the root config of a run-all invocation that apply to its command.
+type stackHooks struct {
+	configPath string
+	hooks      []config.StackHook
+}
+
+// readStackHooks returns the stack hooks of the root config that apply to the given command, or nil if there are none.
+// The root config is the config in the working directory or, if there is none, the nearest one in its parent folders.
+// Root configs without stack_hook and include blocks are not parsed, as root configs that are meant to be included by
+// the configs of the modules often can't be parsed on their own. For the same reason, root configs that fail to parse,
+// such as the config of a module whose locals need the outputs of its dependencies, are skipped with a warning instead
+// of failing the run.
+func readStackHooks(terragruntOptions *options.TerragruntOptions, command string) (*stackHooks, error) {
+	configPath := findRootConfig(terragruntOptions)
+	if configPath == "" || !mayDeclareStackHooks(configPath) {
+		return nil, nil
+	}
+
+	rootOpts := terragruntOptions.Clone(configPath)
+	rootConfig, err := config.PartialParseConfigFile(configPath, rootOpts, nil, []config.PartialDecodeSectionType{config.StackHooksBlock})
+	if err != nil {
+		terragruntOptions.Logger.Warnf("Not running stack hooks, as the root config %s could not be parsed: %v", configPath, err)
+		return nil, nil
+	}
+
+	hooks := []config.StackHook{}
+	for _, hook := range rootConfig.StackHooks {
+		if util.ListContainsElement(hook.Commands, command) {
+			hooks = append(hooks, hook)
+		}
+	}
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+	return &stackHooks{configPath: configPath, hooks: hooks}, nil
+}
+
+// Returns true if the config at the given path has stack_hook blocks, or include blocks through which it may get some,
+// without evaluating it. Configs that can't be parsed as HCL, and configs in the JSON syntax, are assumed to have some.
+func mayDeclareStackHooks(configPath string) bool {
+	file, diags := hclparse.NewParser().ParseHCLFile(configPath)
+	if diags.HasErrors() {
+		return true
+	}
+	body, isNativeSyntax := file.Body.(*hclsyntax.Body)
+	if !isNativeSyntax {
+		return true
+	}
+
+	for _, block := range body.Blocks {
+		if block.Type == config.MetadataStackHooks || block.Type == "include" {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the path of the root config of the run-all invocation of the given options, or an empty string if there is
+// none.
+func findRootConfig(terragruntOptions *options.TerragruntOptions) string {
+	if util.FileExists(terragruntOptions.TerragruntConfigPath) {
+		return terragruntOptions.TerragruntConfigPath
+	}
+
+	dir := terragruntOptions.WorkingDir
+	for {
+		parentDir := filepath.Dir(dir)
+		if parentDir == dir {
+			return ""
+		}
+		dir = parentDir
+
+		if configPath := config.GetDefaultConfigPath(dir); util.FileExists(configPath) {
+			return configPath
+		}
+	}
+}
+
+// runBefore runs the before commands of the hooks, in order, and stops at the first one that fails.
+func (hooks *stackHooks) runBefore(terragruntOptions *options.TerragruntOptions, stack *Stack) error {
+	if hooks == nil {
+		return nil
+	}
+
+	env := map[string]string{
+		stackHookEnvCommand: terragruntOptions.TerraformCommand,
+		stackHookEnvPath:    stack.Path,
+	}
+	for _, hook := range hooks.hooks {
+		if len(hook.Before) == 0 {
+			continue
+		}
+		if err := hooks.run(terragruntOptions, hook, hook.Before, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runAfter runs the after commands of all the hooks, in order, whatever the result of the run, which they get through
+// environment variables along with the path of a JSON run report. Returns the errors of the hooks that failed.
+func (hooks *stackHooks) runAfter(terragruntOptions *options.TerragruntOptions, stack *Stack, report *RunReport, runErr error) error {
+	if hooks == nil {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp("", "terragrunt-stack-hook")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer os.RemoveAll(dir)
+
+	reportFile := filepath.Join(dir, "report.json")
+	if err := report.WriteToFile(reportFile, ReportFormatJSON); err != nil {
+		return err
+	}
+
+	env := map[string]string{
+		stackHookEnvCommand:    terragruntOptions.TerraformCommand,
+		stackHookEnvPath:       stack.Path,
+		stackHookEnvResult:     stackHookResultSuccess,
+		stackHookEnvReportFile: reportFile,
+	}
+	if runErr != nil {
+		env[stackHookEnvResult] = stackHookResultFailure
+		env[stackHookEnvError] = runErr.Error()
+	}
+
+	var errorsOccurred *multierror.Error
+	for _, hook := range hooks.hooks {
+		if len(hook.After) == 0 {
+			continue
+		}
+		if err := hooks.run(terragruntOptions, hook, hook.After, env); err != nil {
+			errorsOccurred = multierror.Append(errorsOccurred, err)
+		}
+	}
+	return errorsOccurred.ErrorOrNil()
+}
+
+// Runs the given command of the given hook with the given environment variables added, in the working_dir of the hook,
+// relative to the folder of the root config, or, if it isn't set, in the folder of the root config.
+func (hooks *stackHooks) run(terragruntOptions *options.TerragruntOptions, hook config.StackHook, command []string, env map[string]string) error {
+	terragruntOptions.Logger.Infof("Executing stack hook: %s", hook.Name)
+
+	hookOpts := terragruntOptions.Clone(hooks.configPath)
+	if hookOpts.Env == nil {
+		hookOpts.Env = map[string]string{}
+	}
+	for key, value := range env {
+		hookOpts.Env[key] = value
+	}
+
+	workingDir := ""
+	if hook.WorkingDir != nil {
+		var err error
+		if workingDir, err = util.CanonicalPath(*hook.WorkingDir, filepath.Dir(hooks.configPath)); err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+
+	if _, err := shell.RunShellCommandWithOutput(hookOpts, workingDir, false, false, command[0], command[1:]...); err != nil {
+		terragruntOptions.Logger.Errorf("Error running stack hook %s with message: %s", hook.Name, err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package configstack
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const stackHooksTestConfig = `
+stack_hook "lock" {
+  commands = ["apply", "destroy"]
+  before   = ["sh", "-c", "echo \"lock $TERRAGRUNT_STACK_COMMAND\" >> hooks.log"]
+  after    = ["sh", "-c", "echo \"unlock $TERRAGRUNT_STACK_RESULT\" >> hooks.log && test -s \"$TERRAGRUNT_STACK_REPORT_FILE\""]
+}
+
+stack_hook "destroy_only" {
+  commands = ["destroy"]
+  before   = ["sh", "-c", "echo destroy >> hooks.log"]
+}
+`
+
+func TestStackRunWithStackHooks(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		moduleErr   error
+		expectedLog string
+	}{
+		{"success", nil, "lock apply\nmodule\nunlock success\n"},
+		{"failure", errors.New("module failed"), "lock apply\nmodule\nunlock failure\n"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			rootDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(rootDir, config.DefaultTerragruntConfigPath), []byte(stackHooksTestConfig), 0644))
+			logPath := filepath.Join(rootDir, "hooks.log")
+
+			// run-all runs in a folder below the root config, which has no config of its own
+			stackDir := filepath.Join(rootDir, "prod")
+			require.NoError(t, os.MkdirAll(stackDir, os.ModePerm))
+
+			opts, err := options.NewTerragruntOptionsForTest(filepath.Join(stackDir, config.DefaultTerragruntConfigPath))
+			require.NoError(t, err)
+			opts.TerraformCommand = "apply"
+			opts.TerraformCliArgs = []string{"apply"}
+
+			moduleOpts, err := options.NewTerragruntOptionsForTest(filepath.Join(stackDir, "app", config.DefaultTerragruntConfigPath))
+			require.NoError(t, err)
+			moduleOpts.RunTerragrunt = func(_ *options.TerragruntOptions) error {
+				logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+				if err != nil {
+					return err
+				}
+				defer logFile.Close()
+				if _, err := logFile.WriteString("module\n"); err != nil {
+					return err
+				}
+				return testCase.moduleErr
+			}
+
+			stack := &Stack{Path: stackDir, Modules: []*TerraformModule{{Path: filepath.Join(stackDir, "app"), TerragruntOptions: moduleOpts}}}
+			err = stack.Run(opts)
+			if testCase.moduleErr != nil {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			log, err := util.ReadFileAsString(logPath)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedLog, log)
+		})
+	}
+}
+
+func TestStackRunBeforeStackHookFails(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	rootConfig := `
+stack_hook "lock" {
+  commands = ["apply"]
+  before   = ["sh", "-c", "exit 1"]
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, config.DefaultTerragruntConfigPath), []byte(rootConfig), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.TerraformCommand = "apply"
+	opts.TerraformCliArgs = []string{"apply"}
+
+	moduleRan := false
+	module := &TerraformModule{Path: filepath.Join(rootDir, "app"), TerragruntOptions: optionsWithMockTerragruntCommand(t, filepath.Join(rootDir, "app", config.DefaultTerragruntConfigPath), nil, &moduleRan)}
+
+	stack := &Stack{Path: rootDir, Modules: []*TerraformModule{module}}
+	assert.Error(t, stack.Run(opts))
+	assert.False(t, moduleRan)
+}
+
+func TestReadStackHooksWithoutRootConfig(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(t.TempDir(), config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+
+	hooks, err := readStackHooks(opts, "apply")
+	require.NoError(t, err)
+	assert.Nil(t, hooks)
+}
+
+func TestReadStackHooksFromIncludedConfig(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "hooks.hcl"), []byte(stackHooksTestConfig), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, config.DefaultTerragruntConfigPath), []byte(`
+include "hooks" {
+  path = "hooks.hcl"
+}
+`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+
+	hooks, err := readStackHooks(opts, "apply")
+	require.NoError(t, err)
+	require.NotNil(t, hooks)
+	require.Len(t, hooks.hooks, 1)
+	assert.Equal(t, "lock", hooks.hooks[0].Name)
+}
+
+func TestReadStackHooksFromUnparsableRootConfig(t *testing.T) {
+	t.Parallel()
+
+	// The root config is only meant to be included, so find_in_parent_folders fails when it is parsed on its own
+	rootDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, config.DefaultTerragruntConfigPath), []byte(`
+# Stack hooks, such as stack_hook "lock", are declared in the configs of the teams
+locals {
+  account = read_terragrunt_config(find_in_parent_folders("account.hcl"))
+}
+`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+
+	hooks, err := readStackHooks(opts, "apply")
+	require.NoError(t, err)
+	assert.Nil(t, hooks)
+}
+
+func TestReadStackHooksSkipsRootConfigThatFailsToParse(t *testing.T) {
+	t.Parallel()
+
+	// run-all runs in the folder of a module whose included config is missing, so its config can't be parsed
+	rootDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, config.DefaultTerragruntConfigPath), []byte(`
+include "root" {
+  path = "${get_terragrunt_dir()}/missing.hcl"
+}
+`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+
+	hooks, err := readStackHooks(opts, "apply")
+	require.NoError(t, err)
+	assert.Nil(t, hooks)
+}
+
+func TestStackHookRelativeWorkingDir(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "scripts"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, config.DefaultTerragruntConfigPath), []byte(`
+stack_hook "lock" {
+  commands    = ["apply"]
+  before      = ["sh", "-c", "touch ran"]
+  working_dir = "scripts"
+}
+`), 0644))
+
+	// run-all runs in a folder below the root config
+	stackDir := filepath.Join(rootDir, "prod")
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(stackDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.TerraformCommand = "apply"
+
+	hooks, err := readStackHooks(opts, "apply")
+	require.NoError(t, err)
+	require.NoError(t, hooks.runBefore(opts, &Stack{Path: stackDir}))
+	assert.True(t, util.FileExists(filepath.Join(rootDir, "scripts", "ran")))
+}
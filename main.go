@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/gruntwork-io/terragrunt/cli"
+	"github.com/gruntwork-io/terragrunt/configstack"
 	"github.com/gruntwork-io/terragrunt/pkg/errors"
 	"github.com/gruntwork-io/terragrunt/shell"
 	"github.com/gruntwork-io/terragrunt/util"
@@ -19,10 +20,15 @@ func main() {
 	checkForErrorsAndExit(err)
 }
 
-// If there is an error, display it in the console and exit with a non-zero exit code. Otherwise, exit 0.
+// If there is an error, display it in the console and exit with a non-zero exit code. Otherwise, exit 0. A run-all plan
+// with -detailed-exitcode whose only "error" is that some plans have changes succeeded, so it exits 2 without reporting
+// an error.
 func checkForErrorsAndExit(err error) {
 	if err == nil {
 		os.Exit(0)
+	} else if planHasChanges, isPlanHasChanges := errors.Unwrap(err).(configstack.PlanHasChanges); isPlanHasChanges {
+		util.GlobalFallbackLogEntry.Infof(planHasChanges.Error())
+		os.Exit(shell.DetailedExitCodeChanges)
 	} else {
 		util.GlobalFallbackLogEntry.Debugf(errors.PrintErrorWithStackTrace(err))
 		util.GlobalFallbackLogEntry.Errorf(err.Error())
@@ -0,0 +1,39 @@
+package options
+
+import (
+	"sort"
+	"sync"
+)
+
+// FileReadTracker records the paths of the files that are read while parsing a Terragrunt config, with
+// read_terragrunt_config or sops_decrypt_file, so that callers can find out which files a module depends on besides its
+// own config.
+type FileReadTracker struct {
+	paths map[string]bool
+	mutex sync.Mutex
+}
+
+// NewFileReadTracker creates an empty FileReadTracker.
+func NewFileReadTracker() *FileReadTracker {
+	return &FileReadTracker{paths: map[string]bool{}}
+}
+
+// Add records that the file at the given path was read.
+func (tracker *FileReadTracker) Add(path string) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	tracker.paths[path] = true
+}
+
+// Paths returns the sorted list of the paths recorded so far.
+func (tracker *FileReadTracker) Paths() []string {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	paths := make([]string, 0, len(tracker.paths))
+	for path := range tracker.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -38,6 +38,12 @@ const (
 	DefaultTFDataDir = ".terraform"
 
 	DefaultIAMAssumeRoleDuration = 3600
+
+	// How long terraform gets to shut down gracefully after being interrupted because it timed out, before it is killed
+	DefaultTimeoutGracePeriod = 30 * time.Second
+
+	// How often a shard of a distributed run-all checks whether the modules it waits for have finished on other shards
+	DefaultShardPollInterval = 10 * time.Second
 )
 
 const ContextKey ctxKey = iota
@@ -223,6 +229,128 @@ type TerragruntOptions struct {
 
 	// Controls if a module prefix will be prepended to TF outputs
 	IncludeModulePrefix bool
+
+	// The file path where run-all should write a machine-readable report with the outcome of each module.
+	ReportFile string
+
+	// The format of the run-all report (json or junit). If empty, the format is inferred from the ReportFile extension.
+	ReportFormat string
+
+	// If set to true, run-all skips the modules that finished successfully in the previous, failed run of the same
+	// command, as recorded in the checkpoint file.
+	Resume bool
+
+	// The file where run-all records the modules that finished successfully when the run fails. If empty, the file is
+	// placed in the download dir.
+	CheckpointFile string
+
+	// If set to true, run-all stops on the first module that fails: modules that have not started yet are skipped and
+	// running modules are interrupted.
+	FailFast bool
+
+	// Context of the run these options belong to. When it is cancelled, shell commands started with these options are
+	// interrupted. Nil means the run can't be cancelled.
+	Context context.Context
+
+	// A git ref. If set, run-all only runs the modules affected by the files changed between this ref and HEAD, plus
+	// every module that depends on them.
+	ChangedSince string
+
+	// If set, the paths of the files read while parsing the Terragrunt config (through read_terragrunt_config or
+	// sops_decrypt_file) are recorded here. Shared by the clones of the options so that nested reads are recorded too.
+	FileReadTracker *FileReadTracker
+
+	// The maximum time a module may run, as a duration string such as "30m". Overrides the terraform_timeout attribute
+	// of the config. Empty means no timeout.
+	Timeout string
+
+	// How long terraform gets to shut down gracefully after it was interrupted because of a timeout, before it is killed.
+	TimeoutGracePeriod time.Duration
+
+	// The maximum number of modules of each concurrency group that run-all may run at the same time. Overrides the
+	// concurrency_limits attribute of the configs.
+	ConcurrencyLimits map[string]int
+
+	// If set to true, run-all plan prints a table that summarizes the resource changes planned in each module.
+	PlanSummary bool
+
+	// If set, the plan command saves the plan and writes its JSON representation, as printed by `terraform show -json`,
+	// to this file. Set by run-all plan on each module to build the plan summary.
+	PlanJSONFile string
+
+	// If set to true, the graph dependents and dependencies commands also list the modules that are connected to the
+	// given module indirectly, through other modules.
+	GraphTransitive bool
+
+	// If set to true, the graph commands print the modules grouped in the order in which run-all would run them.
+	GraphRunOrder bool
+
+	// If set to true, the graph commands print JSON instead of plain text.
+	GraphJSON bool
+
+	// The format in which graph-dependencies prints the dependency graph: dot, mermaid or json. Empty means dot.
+	GraphFormat string
+
+	// The path of a JSON run report, as written by --terragrunt-report-file, used by graph-dependencies to colour each
+	// module by the result of its last run.
+	GraphRunReport string
+
+	// The shard of a distributed run-all that this worker runs, as "index/count", such as "2/5". Empty means the
+	// run-all is not distributed.
+	Shard string
+
+	// The location, either a local folder or an S3 URL, of the done markers through which the shards of a distributed
+	// run-all learn that the modules run by other shards have finished.
+	ShardMarkerStore string
+
+	// The ID of the distributed run-all, shared by all its shards, under which the done markers are recorded, so that
+	// the markers of other runs are ignored. Empty means the ID of the CI run, if it can be found in the environment.
+	ShardRunID string
+
+	// How often a shard checks whether the modules it waits for have finished on other shards.
+	ShardPollInterval time.Duration
+
+	// The maximum time a shard waits for a module to finish on another shard, as a duration string such as "2h", after
+	// which the modules that depend on it fail. Empty means the shard waits forever.
+	ShardWaitTimeout string
+
+	// The path of the file in which the parts of the module configs that are parsed to find a stack are cached between
+	// runs. Empty means the configs are parsed on every run.
+	DiscoveryCacheFile string
+
+	// If set to true, run-all apply and destroy plan each module first, show the plan and only apply it if the user
+	// approves it. Modules that depend on a rejected module are skipped.
+	RunAllConfirmEach bool
+
+	// If set to true, run-all output prints the outputs of all the modules as a single JSON object, keyed by the path of
+	// each module relative to the stack.
+	MergeOutputJSON bool
+
+	// The number of times run-all runs each module at most. When modules fail, the failed modules and the modules that
+	// were skipped because of them are run again, in dependency order, after an exponential backoff that starts at
+	// RetrySleepIntervalSec. Values below 2 disable these retries.
+	RunAllRetryMaxAttempts int
+
+	// How run-all writes the output of the modules: stream, the default, writes it as it is produced, and buffered writes
+	// the output of each module as one block once the module finishes.
+	OutputMode string
+
+	// The format in which validate-config prints the problems it finds in the configs: text, the default, prints one
+	// line per problem, like a compiler, and sarif prints a SARIF log, which code review tools can show as annotations.
+	DiagnosticsFormat string
+
+	// If set to true, the unknown values of the inputs, generate and remote_state attributes are converted to null
+	// instead of failing the parsing of the config. Only set by validate-config, where the outputs of the dependencies
+	// without mock_outputs are unknown.
+	UnknownValuesAsNull bool
+
+	// Whether run_cmd calls with --terragrunt-cache-ttl cache their output on disk, so that other Terragrunt processes
+	// reuse it until the TTL expires
+	CmdCache bool
+
+	// The directory in which the output of run_cmd calls with --terragrunt-cache-ttl is cached. Empty means the
+	// terragrunt/run_cmd directory of the user cache directory.
+	CmdCacheDir string
 }
 
 // IAMOptions represents options that are used by Terragrunt to assume an IAM role.
@@ -270,6 +398,7 @@ func NewTerragruntOptions() *TerragruntOptions {
 		Env:                            map[string]string{},
 		Source:                         "",
 		SourceMap:                      map[string]string{},
+		ConcurrencyLimits:              map[string]int{},
 		SourceUpdate:                   false,
 		IgnoreDependencyErrors:         false,
 		IgnoreDependencyOrder:          false,
@@ -279,6 +408,7 @@ func NewTerragruntOptions() *TerragruntOptions {
 		ErrWriter:                      os.Stderr,
 		MaxFoldersToCheck:              DefaultMaxFoldersToCheck,
 		AutoRetry:                      true,
+		CmdCache:                       true,
 		RetryMaxAttempts:               DEFAULT_RETRY_MAX_ATTEMPTS,
 		RetrySleepIntervalSec:          DEFAULT_RETRY_SLEEP_INTERVAL_SEC,
 		RetryableErrors:                util.CloneStringList(DEFAULT_RETRYABLE_ERRORS),
@@ -294,6 +424,8 @@ func NewTerragruntOptions() *TerragruntOptions {
 		OutputPrefix:                   "",
 		IncludeModulePrefix:            false,
 		JSONOut:                        DefaultJSONOutName,
+		TimeoutGracePeriod:             DefaultTimeoutGracePeriod,
+		ShardPollInterval:              DefaultShardPollInterval,
 		RunTerragrunt: func(opts *TerragruntOptions) error {
 			return errors.WithStackTrace(RunTerragruntCommandNotSet)
 		},
@@ -416,6 +548,38 @@ func (opts *TerragruntOptions) Clone(terragruntConfigPath string) *TerragruntOpt
 		UsePartialParseConfigCache:     opts.UsePartialParseConfigCache,
 		OutputPrefix:                   opts.OutputPrefix,
 		IncludeModulePrefix:            opts.IncludeModulePrefix,
+		ReportFile:                     opts.ReportFile,
+		ReportFormat:                   opts.ReportFormat,
+		Resume:                         opts.Resume,
+		CheckpointFile:                 opts.CheckpointFile,
+		FailFast:                       opts.FailFast,
+		Context:                        opts.Context,
+		ChangedSince:                   opts.ChangedSince,
+		FileReadTracker:                opts.FileReadTracker,
+		Timeout:                        opts.Timeout,
+		TimeoutGracePeriod:             opts.TimeoutGracePeriod,
+		ConcurrencyLimits:              opts.ConcurrencyLimits,
+		PlanSummary:                    opts.PlanSummary,
+		PlanJSONFile:                   opts.PlanJSONFile,
+		GraphTransitive:                opts.GraphTransitive,
+		GraphRunOrder:                  opts.GraphRunOrder,
+		GraphJSON:                      opts.GraphJSON,
+		GraphFormat:                    opts.GraphFormat,
+		GraphRunReport:                 opts.GraphRunReport,
+		Shard:                          opts.Shard,
+		ShardMarkerStore:               opts.ShardMarkerStore,
+		ShardRunID:                     opts.ShardRunID,
+		ShardPollInterval:              opts.ShardPollInterval,
+		ShardWaitTimeout:               opts.ShardWaitTimeout,
+		DiscoveryCacheFile:             opts.DiscoveryCacheFile,
+		RunAllConfirmEach:              opts.RunAllConfirmEach,
+		MergeOutputJSON:                opts.MergeOutputJSON,
+		RunAllRetryMaxAttempts:         opts.RunAllRetryMaxAttempts,
+		OutputMode:                     opts.OutputMode,
+		DiagnosticsFormat:              opts.DiagnosticsFormat,
+		UnknownValuesAsNull:            opts.UnknownValuesAsNull,
+		CmdCache:                       opts.CmdCache,
+		CmdCacheDir:                    opts.CmdCacheDir,
 	}
 }
 
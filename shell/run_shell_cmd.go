@@ -2,6 +2,8 @@ package shell
 
 import (
 	"bytes"
+	"context"
+	goerrors "errors"
 	"fmt"
 	"io"
 	"os"
@@ -117,7 +119,7 @@ func RunShellCommandWithOutput(
 
 	// Make sure to forward signals to the subcommand.
 	cmdChannel := make(chan error) // used for closing the signals forwarder goroutine
-	signalChannel := NewSignalsForwarder(forwardSignals, cmd, terragruntOptions.Logger, cmdChannel)
+	signalChannel := NewSignalsForwarderWithContext(terragruntOptions.Context, terragruntOptions.TimeoutGracePeriod, forwardSignals, cmd, terragruntOptions.Logger, cmdChannel)
 	defer signalChannel.Close()
 
 	err := cmd.Wait()
@@ -153,6 +155,19 @@ func isTerraformCommandThatNeedsPty(command string) bool {
 	return util.ListContainsElement(terraformCommandsThatNeedPty, command)
 }
 
+// The exit code with which plan -detailed-exitcode, and the drift command, report that there are changes.
+const DetailedExitCodeChanges = 2
+
+// IsPlanWithChanges returns true if the given error is the exit code with which plan -detailed-exitcode, run with the
+// given terraform arguments, reports that the plan succeeded and has changes.
+func IsPlanWithChanges(args []string, err error) bool {
+	if util.FirstArg(args) != "plan" || !util.ListContainsElement(args, "-detailed-exitcode") {
+		return false
+	}
+	exitCode, exitCodeErr := GetExitCode(err)
+	return exitCodeErr == nil && exitCode == DetailedExitCodeChanges
+}
+
 // Return the exit code of a command. If the error does not implement errors.IErrorCode or is not an exec.ExitError
 // or *multierror.Error type, the error is returned.
 func GetExitCode(err error) (int, error) {
@@ -185,16 +200,61 @@ func withPrefix(writer io.Writer, prefix string) io.Writer {
 	return util.PrefixedWriter(writer, prefix)
 }
 
+// NotifyForwardedSignals returns a copy of the given context that is done as soon as Terragrunt receives one of the
+// signals it forwards to the commands it runs, e.g. SIGINT on Ctrl-C, or the returned stop function is called. The
+// signals are still forwarded to the running commands as usual.
+func NotifyForwardedSignals(ctx context.Context) (context.Context, context.CancelFunc) {
+	// signal.NotifyContext relays all the signals when given none
+	if len(forwardSignals) == 0 {
+		return context.WithCancel(ctx)
+	}
+	return signal.NotifyContext(ctx, forwardSignals...)
+}
+
 type SignalsForwarder chan os.Signal
 
 // Forwards signals to a command, waiting for the command to finish.
 func NewSignalsForwarder(signals []os.Signal, c *exec.Cmd, logger *logrus.Entry, cmdChannel chan error) SignalsForwarder {
+	return NewSignalsForwarderWithContext(context.Background(), 0, signals, c, logger, cmdChannel)
+}
+
+// Forwards signals to a command, waiting for the command to finish. In addition, the command is interrupted as soon as
+// the given context is cancelled, e.g. when another module fails in a run-all command with --terragrunt-fail-fast. If
+// the context was cancelled because its deadline passed, i.e. the command timed out, and the command is still running
+// after killDelay, it is killed.
+func NewSignalsForwarderWithContext(ctx context.Context, killDelay time.Duration, signals []os.Signal, c *exec.Cmd, logger *logrus.Entry, cmdChannel chan error) SignalsForwarder {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	signalChannel := make(chan os.Signal, 1)
 	signal.Notify(signalChannel, signals...)
 
 	go func() {
+		ctxDone := ctx.Done()
+		var killTimer <-chan time.Time
+
 		for {
 			select {
+			case <-ctxDone:
+				// Only interrupt once, a nil channel blocks forever.
+				ctxDone = nil
+
+				logger.Debugf("Run was cancelled (%v). Sending %v to terraform.", ctx.Err(), interruptSignal)
+				if err := c.Process.Signal(interruptSignal); err != nil {
+					logger.Errorf("Error sending %v to the command: %v", interruptSignal, err)
+				}
+
+				if goerrors.Is(ctx.Err(), context.DeadlineExceeded) && killDelay > 0 {
+					killTimer = time.After(killDelay)
+				}
+			case <-killTimer:
+				killTimer = nil
+
+				logger.Warnf("Terraform did not shut down within %v after it timed out. Killing it.", killDelay)
+				if err := c.Process.Kill(); err != nil {
+					logger.Errorf("Error killing the command: %v", err)
+				}
 			case s := <-signalChannel:
 				logger.Debugf("%s signal received. Gracefully shutting down... (it can take up to %v)", strings.Title(s.String()), signalForwardingDelay)
 
@@ -249,6 +309,41 @@ func GitTopLevelDir(terragruntOptions *options.TerragruntOptions, path string) (
 	return strings.TrimSpace(cmd.Stdout), nil
 }
 
+// GitChangedFiles returns the absolute paths of the files that changed between the given ref and HEAD in the git
+// repository containing the given path. The diff is taken from the merge base of the ref and HEAD, so that changes
+// made on the ref after the current branch was created are not included.
+func GitChangedFiles(terragruntOptions *options.TerragruntOptions, path string, ref string) ([]string, error) {
+	topLevelDir, err := GitTopLevelDir(terragruntOptions, path)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	opts, err := options.NewTerragruntOptionsWithConfigPath(topLevelDir)
+	if err != nil {
+		return nil, err
+	}
+	opts.Env = terragruntOptions.Env
+	opts.Writer = &stdout
+	opts.ErrWriter = &stderr
+	cmd, err := RunShellCommandWithOutput(opts, topLevelDir, true, false, "git", "diff", "--name-only", ref+"...HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	changedFiles := []string{}
+	for _, line := range strings.Split(cmd.Stdout, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		changedFiles = append(changedFiles, util.JoinPath(topLevelDir, line))
+	}
+	terragruntOptions.Logger.Debugf("Files changed since %s: %v", ref, changedFiles)
+	return changedFiles, nil
+}
+
 // ProcessExecutionError - error returned when a command fails, contains StdOut and StdErr
 type ProcessExecutionError struct {
 	Err        error
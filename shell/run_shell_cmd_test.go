@@ -2,11 +2,15 @@ package shell
 
 import (
 	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRunShellCommand(t *testing.T) {
@@ -22,6 +26,19 @@ func TestRunShellCommand(t *testing.T) {
 	assert.Error(t, cmd)
 }
 
+func TestIsPlanWithChanges(t *testing.T) {
+	t.Parallel()
+
+	exitCodeChanges := exec.Command("sh", "-c", "exit 2").Run()
+	exitCodeError := exec.Command("sh", "-c", "exit 1").Run()
+
+	assert.True(t, IsPlanWithChanges([]string{"plan", "-detailed-exitcode"}, exitCodeChanges))
+	assert.False(t, IsPlanWithChanges([]string{"plan", "-detailed-exitcode"}, exitCodeError))
+	assert.False(t, IsPlanWithChanges([]string{"plan"}, exitCodeChanges))
+	assert.False(t, IsPlanWithChanges([]string{"apply", "-detailed-exitcode"}, exitCodeChanges))
+	assert.False(t, IsPlanWithChanges([]string{"plan", "-detailed-exitcode"}, nil))
+}
+
 func TestRunShellOutputToStderrAndStdout(t *testing.T) {
 	t.Parallel()
 
@@ -53,3 +70,44 @@ func TestRunShellOutputToStderrAndStdout(t *testing.T) {
 	assert.True(t, strings.Contains(stderr.String(), "Terraform"), "Output directed to stderr")
 	assert.True(t, len(stdout.String()) == 0, "No output to stdout")
 }
+
+func TestGitChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	repoDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	writeFile := func(path string, contents string) {
+		fullPath := filepath.Join(repoDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), os.ModePerm))
+		require.NoError(t, os.WriteFile(fullPath, []byte(contents), 0644))
+	}
+
+	git("init", "--quiet")
+	writeFile("a/terragrunt.hcl", "")
+	writeFile("b/terragrunt.hcl", "")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "initial")
+	git("tag", "base")
+
+	writeFile("b/terragrunt.hcl", "inputs = {}")
+	writeFile("c/main.tf", "")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "change")
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+
+	changedFiles, err := GitChangedFiles(terragruntOptions, filepath.Join(repoDir, "a"), "base")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.ToSlash(filepath.Join(repoDir, "b/terragrunt.hcl")),
+		filepath.ToSlash(filepath.Join(repoDir, "c/main.tf")),
+	}, changedFiles)
+}
@@ -4,6 +4,7 @@
 package shell
 
 import (
+	"context"
 	goerrors "errors"
 	"fmt"
 	"os"
@@ -143,3 +144,50 @@ func TestRunShellCommandWithOutputInterrupt(t *testing.T) {
 	expectedErr := fmt.Sprintf("[.] exit status %d", expectedWait)
 	assert.EqualError(t, <-errCh, expectedErr)
 }
+
+func TestRunShellCommandWithOutputContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("")
+	assert.Nil(t, err, "Unexpected error creating NewTerragruntOptionsForTest: %v", err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	terragruntOptions.Context = ctx
+
+	errCh := make(chan error)
+	expectedWait := 1
+
+	go func() {
+		_, err := RunShellCommandWithOutput(terragruntOptions, "", false, false, "../testdata/test_sigint_wait.sh", strconv.Itoa(expectedWait))
+		errCh <- err
+	}()
+
+	time.AfterFunc(time.Second, cancel)
+
+	expectedErr := fmt.Sprintf("[.] exit status %d", expectedWait)
+	assert.EqualError(t, <-errCh, expectedErr)
+}
+
+func TestRunShellCommandWithOutputTimeoutKillsAfterGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("")
+	assert.Nil(t, err, "Unexpected error creating NewTerragruntOptionsForTest: %v", err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	terragruntOptions.Context = ctx
+	terragruntOptions.TimeoutGracePeriod = 500 * time.Millisecond
+
+	// The command ignores SIGINT, so it only stops when it is killed.
+	start := time.Now()
+	_, err = RunShellCommandWithOutput(terragruntOptions, "", false, false, "sh", "-c", "trap '' INT; exec sleep 30")
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	var processErr ProcessExecutionError
+	if assert.True(t, goerrors.As(err, &processErr)) {
+		status := processErr.Err.(*exec.ExitError).Sys().(syscall.WaitStatus)
+		assert.Equal(t, syscall.SIGKILL, status.Signal())
+	}
+}
@@ -9,3 +9,6 @@ import (
 )
 
 var forwardSignals []os.Signal = []os.Signal{syscall.SIGTERM, syscall.SIGINT}
+
+// The signal sent to a running command when its run is cancelled.
+var interruptSignal os.Signal = syscall.SIGINT
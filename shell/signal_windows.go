@@ -8,3 +8,7 @@ import (
 )
 
 var forwardSignals []os.Signal = []os.Signal{}
+
+// The signal sent to a running command when its run is cancelled. Windows does not support sending os.Interrupt to
+// another process, so the command is killed instead.
+var interruptSignal os.Signal = os.Kill
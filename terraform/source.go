@@ -222,6 +222,20 @@ func IsLocalSource(sourceUrl *url.URL) bool {
 	return sourceUrl.Scheme == "file"
 }
 
+// Returns the absolute path of the folder that the given source refers to, if it is a path on the local file system.
+// Relative sources are resolved against the given working dir. The second return value is false if the source is not
+// local.
+func LocalSourcePath(source string, workingDir string) (string, bool, error) {
+	sourceUrl, err := toSourceUrl(source, workingDir)
+	if err != nil {
+		return "", false, err
+	}
+	if !IsLocalSource(sourceUrl) {
+		return "", false, nil
+	}
+	return util.CleanPath(sourceUrl.Path), true, nil
+}
+
 // Splits a source URL into the root repo and the path. The root repo is the part of the URL before the double-slash
 // (//), which typically represents the root of a modules repo (e.g. github.com/foo/infrastructure-modules) and the
 // path is everything after the double slash. If there is no double-slash in the URL, the root repo is the entire
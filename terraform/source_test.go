@@ -77,3 +77,31 @@ func TestRegressionSupportForGitRemoteCodecommit(t *testing.T) {
 	require.Equal(t, "git::codecommit::ap-northeast-1://my_app_modules", actualRootRepo.String())
 	require.Equal(t, "my-app/modules/main-module", actualModulePath)
 }
+
+func TestLocalSourcePath(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		source        string
+		expectedPath  string
+		expectedLocal bool
+	}{
+		{"../modules/vpc", "/infra/modules/vpc", true},
+		{"../modules//vpc", "/infra/modules/vpc", true},
+		{"/opt/modules/vpc", "/opt/modules/vpc", true},
+		{"git::git@github.com:foo/modules.git//vpc?ref=v0.0.1", "", false},
+		{"github.com/foo/modules//vpc", "", false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.source, func(t *testing.T) {
+			t.Parallel()
+
+			actualPath, actualLocal, err := LocalSourcePath(testCase.source, "/infra/live")
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedLocal, actualLocal)
+			assert.Equal(t, testCase.expectedPath, actualPath)
+		})
+	}
+}
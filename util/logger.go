@@ -45,9 +45,11 @@ func CreateLogger(lvl logrus.Level) *logrus.Logger {
 	logger := logrus.New()
 	logger.SetLevel(lvl)
 	logger.SetOutput(os.Stderr) //Terragrunt should output all it's logs to stderr by default
-	logger.SetFormatter(&logrus.TextFormatter{
-		DisableQuote:  true,
-		DisableColors: disableLogColors,
+	logger.SetFormatter(&RedactingFormatter{
+		OriginalFormatter: &logrus.TextFormatter{
+			DisableQuote:  true,
+			DisableColors: disableLogColors,
+		},
 	})
 	return logger
 }
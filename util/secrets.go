@@ -0,0 +1,153 @@
+package util
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactedSecret is what secret values are replaced with in the output of Terragrunt
+const RedactedSecret = "[REDACTED]"
+
+// Secret values shorter than this are not redacted, as replacing them would also mangle unrelated text, such as a
+// secret "1" that would redact every 1 in the logs. Longer values are only redacted where they are not part of a longer
+// word, see RedactSecrets.
+const minRedactedSecretLength = 4
+
+var (
+	secretValues      = map[string]bool{}
+	secretValuesMutex sync.RWMutex
+)
+
+// RegisterSecretValue registers the given value as a secret, to be redacted by RedactSecrets from then on, for the rest
+// of the process. Leading and trailing whitespace are ignored, as secrets read from files often end with a newline. The
+// value is also registered as it appears in JSON strings, such as the ones of the TF_VAR_ env vars of maps, if it has
+// characters that JSON escapes.
+func RegisterSecretValue(value string) {
+	value = strings.TrimSpace(value)
+	if len(value) < minRedactedSecretLength {
+		return
+	}
+
+	secretValuesMutex.Lock()
+	defer secretValuesMutex.Unlock()
+	secretValues[value] = true
+	if encoded, err := json.Marshal(value); err == nil {
+		secretValues[string(encoded[1:len(encoded)-1])] = true
+	}
+}
+
+// RedactSecrets returns the given text with all registered secret values replaced by RedactedSecret. Values are only
+// replaced where they don't start or end in the middle of a word, so that a secret "prod" redacts "env=prod" but not
+// "production".
+func RedactSecrets(text string) string {
+	secretValuesMutex.RLock()
+	defer secretValuesMutex.RUnlock()
+
+	if len(secretValues) == 0 {
+		return text
+	}
+
+	// Replace the longest secrets first, so that a secret that contains another is redacted as a whole
+	values := make([]string, 0, len(secretValues))
+	for value := range secretValues {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	for _, value := range values {
+		text = redactWholeWords(text, value)
+	}
+	return text
+}
+
+// Returns the given text with the occurrences of value that don't join a word before or after them replaced by
+// RedactedSecret.
+func redactWholeWords(text, value string) string {
+	var redacted strings.Builder
+	// The start of the text that is not written to redacted yet
+	start := 0
+
+	for searchFrom := 0; ; {
+		i := strings.Index(text[searchFrom:], value)
+		if i < 0 {
+			break
+		}
+		i += searchFrom
+		end := i + len(value)
+
+		if joinsWord(text[:i], value) || joinsWord(value, text[end:]) {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			searchFrom = i + size
+			continue
+		}
+		redacted.WriteString(text[start:i])
+		redacted.WriteString(RedactedSecret)
+		start, searchFrom = end, end
+	}
+
+	if start == 0 {
+		return text
+	}
+	redacted.WriteString(text[start:])
+	return redacted.String()
+}
+
+// Returns true if before ends and after starts with a letter, digit or underscore, i.e. if they are parts of the same
+// word.
+func joinsWord(before, after string) bool {
+	last, _ := utf8.DecodeLastRuneInString(before)
+	first, _ := utf8.DecodeRuneInString(after)
+	return isWordRune(last) && isWordRune(first)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// ContainsSecret returns true if RedactSecrets would redact any registered secret value from the given text.
+func ContainsSecret(text string) bool {
+	return RedactSecrets(text) != text
+}
+
+// RedactSecretsInValue returns a copy of the given value, as decoded from JSON, with all registered secret values
+// redacted from its strings, including the ones nested in maps and lists.
+func RedactSecretsInValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		return RedactSecrets(value)
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			redacted[key] = RedactSecretsInValue(item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(value))
+		for i, item := range value {
+			redacted[i] = RedactSecretsInValue(item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// RedactingFormatter is a logrus formatter that redacts all registered secret values from the log entries formatted by
+// the wrapped formatter.
+type RedactingFormatter struct {
+	OriginalFormatter logrus.Formatter
+}
+
+func (formatter *RedactingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	formatted, err := formatter.OriginalFormatter.Format(entry)
+	if err != nil {
+		return formatted, err
+	}
+	return []byte(RedactSecrets(string(formatted))), nil
+}
@@ -0,0 +1,60 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	t.Parallel()
+
+	RegisterSecretValue("redact-test-password\n")
+	RegisterSecretValue("redact-test-password-long")
+	RegisterSecretValue("abc")
+
+	assert.Equal(t, "password=[REDACTED], abc", RedactSecrets("password=redact-test-password, abc"))
+	// A secret that contains another is redacted as a whole
+	assert.Equal(t, "[REDACTED]", RedactSecrets("redact-test-password-long"))
+
+	// Secrets are also redacted from JSON, where their special characters are escaped
+	RegisterSecretValue("redact-test-\"quoted\"")
+	assert.Equal(t, `{"password":"[REDACTED]"}`, RedactSecrets(`{"password":"redact-test-\"quoted\""}`))
+
+	assert.Equal(t, map[string]interface{}{
+		"db":    map[string]interface{}{"password": "[REDACTED]", "port": float64(5432)},
+		"hosts": []interface{}{"db-1", "[REDACTED]"},
+	}, RedactSecretsInValue(map[string]interface{}{
+		"db":    map[string]interface{}{"password": "redact-test-password", "port": float64(5432)},
+		"hosts": []interface{}{"db-1", "redact-test-password"},
+	}))
+}
+
+func TestRedactSecretsWholeWords(t *testing.T) {
+	t.Parallel()
+
+	RegisterSecretValue("prod")
+	RegisterSecretValue("true")
+
+	assert.Equal(t, "env=[REDACTED], enabled: [REDACTED]", RedactSecrets("env=prod, enabled: true"))
+	assert.Equal(t, "[REDACTED]-vpc in [REDACTED]", RedactSecrets("prod-vpc in prod"))
+	assert.Equal(t, "production, untrue, trueish, prod_1", RedactSecrets("production, untrue, trueish, prod_1"))
+	assert.Equal(t, "productionprod [REDACTED]", RedactSecrets("productionprod prod"))
+	assert.False(t, ContainsSecret("deploy to production"))
+}
+
+func TestRedactingFormatter(t *testing.T) {
+	t.Parallel()
+
+	RegisterSecretValue("formatter-test-token")
+
+	var out bytes.Buffer
+	logger := CreateLogger(logrus.DebugLevel)
+	logger.SetOutput(&out)
+	logger.Debugf("Using token formatter-test-token")
+
+	assert.Contains(t, out.String(), "Using token [REDACTED]")
+	assert.NotContains(t, out.String(), "formatter-test-token")
+}